@@ -1,18 +1,61 @@
 package list
 
 import (
+	"bytes"
 	"container/list"
+	"context"
+	"encoding/binary"
+	"errors"
 	"github.com/roseduan/rosedb/storage"
+	"hash/fnv"
+	"math/rand"
 	"reflect"
+	"sort"
+	"sync"
+	"time"
 )
 
 // List is the implementation of doubly linked list.
 
+var (
+	// ErrInvalidData the data to unmarshal is invalid.
+	ErrInvalidData = errors.New("ds/list: invalid data to unmarshal")
+
+	// ErrKeyNotFound the key of list does not exist.
+	ErrKeyNotFound = errors.New("ds/list: key not found")
+
+	// ErrEmptyList the list is empty.
+	ErrEmptyList = errors.New("ds/list: list is empty")
+
+	// ErrValueTooLarge a value is larger than the max value size.
+	ErrValueTooLarge = errors.New("ds/list: value is too large")
+)
+
 // InsertOption insert option for LInsert.
 type InsertOption uint8
 
 type dumpFunc func(e *storage.Entry) error
 
+type dumpBatchFunc func(key []byte, vals [][]byte) error
+
+// NodeOverhead is the approximate memory in bytes used by the bookkeeping of each element in a list,
+// including the prev, next and list pointers, the interface value and the boxed slice header.
+const NodeOverhead = 64
+
+// The inclusive upper bounds of the length buckets of ListStats.LenHistogram.
+// A list is counted in the first bucket whose bound is not less than its length,
+// and lists longer than LenBucketLarge are counted in the last bucket.
+const (
+	LenBucketEmpty  = 0
+	LenBucketTiny   = 10
+	LenBucketSmall  = 100
+	LenBucketMedium = 1000
+	LenBucketLarge  = 10000
+)
+
+// lenBucketBounds the bounds of all buckets except the last one.
+var lenBucketBounds = [...]int{LenBucketEmpty, LenBucketTiny, LenBucketSmall, LenBucketMedium, LenBucketLarge}
+
 const (
 	// Before insert before pivot.
 	Before InsertOption = iota
@@ -22,158 +65,2718 @@ const (
 
 type (
 	// List list idx.
+	// It is safe for concurrent use by multiple goroutines.
 	List struct {
+		mu sync.RWMutex
 		// record saves the List of a specified key.
 		record Record
+		// version saves the version of every key, which changes on every mutation of the list.
+		version map[string]uint64
+		// seq is the last assigned version, versions are never reused even if a key is removed and created again.
+		seq uint64
+		// expires saves the expiration time of keys in unix nanoseconds, keys without ttl are not in it.
+		expires map[string]int64
+		// hints saves the last found element of every key to speed up repeated lookups of the same value.
+		hints map[string]findHint
+		// maxValueSize is the max size of a value that can be added to List, 0 means unlimited.
+		maxValueSize int
+		// watchers saves the channels registered by LWatch of every key.
+		watchers map[string]map[chan struct{}]struct{}
+	}
+
+	// ListStats the statistics of all the lists.
+	ListStats struct {
+		// Keys the number of keys.
+		Keys int
+		// TotalElements the total number of elements of all the lists.
+		TotalElements int
+		// MaxLen the length of the longest list.
+		MaxLen int
+		// MaxLenKey the key of the longest list.
+		MaxLenKey string
+		// LenHistogram the number of lists in every length bucket, see LenBucketEmpty.
+		LenHistogram [len(lenBucketBounds) + 1]int
+	}
+
+	// findHint is the result of the last lookup of a key, it is only valid while the version of the key is unchanged.
+	findHint struct {
+		version uint64
+		val     []byte
+		e       *list.Element
 	}
 
 	// Record list record to save.
 	Record map[string]*list.List
+
+	// ListIterator iterates the elements of a list from head to tail lazily.
+	ListIterator struct {
+		lis     *List
+		item    *list.List
+		e       *list.Element
+		started bool
+	}
 )
 
 // New create a new list idx.
 func New() *List {
 	return &List{
-		make(Record),
+		record:   make(Record),
+		version:  make(map[string]uint64),
+		expires:  make(map[string]int64),
+		hints:    make(map[string]findHint),
+		watchers: make(map[string]map[chan struct{}]struct{}),
 	}
 }
 
 // DumpIterate iterate all keys and values for dump.
+// fn is called with the read lock held, so it must not call the methods of List.
 func (lis *List) DumpIterate(fn dumpFunc) (err error) {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
 	for key, l := range lis.record {
-		listKey := []byte(key)
+		if err = lis.dumpKey(key, l, fn); err != nil {
+			return
+		}
+	}
+	return
+}
 
-		for e := l.Front(); e != nil; e = e.Next() {
-			value, _ := e.Value.([]byte)
-			// List ListRPush
-			ent := storage.NewEntryNoExtra(listKey, value, 1, 1)
-			if err = fn(ent); err != nil {
-				return
+// DumpIterateKeys iterate the given keys and their values for dump, keys that do not exist are skipped.
+// If no key is given, it is the same as DumpIterate.
+// fn is called with the read lock held, so it must not call the methods of List.
+func (lis *List) DumpIterateKeys(fn dumpFunc, keys ...string) (err error) {
+	if len(keys) == 0 {
+		return lis.DumpIterate(fn)
+	}
+
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	for _, key := range keys {
+		l, ok := lis.record[key]
+		if !ok {
+			continue
+		}
+		if err = lis.dumpKey(key, l, fn); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// DumpIterateBatched iterate all keys for dump, fn is called once for every key with all its values from head to tail.
+// Compared to DumpIterate, it allows the caller to write a list as a single record.
+// The values passed to fn are the stored values, so they must not be modified.
+// fn is called with the read lock held, so it must not call the methods of List.
+func (lis *List) DumpIterateBatched(fn dumpBatchFunc) (err error) {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	for key, l := range lis.record {
+		var vals [][]byte
+		if l != nil {
+			vals = make([][]byte, 0, l.Len())
+			for e := l.Front(); e != nil; e = e.Next() {
+				vals = append(vals, valueOf(e))
 			}
 		}
+		if err = fn([]byte(key), vals); err != nil {
+			return
+		}
 	}
 	return
 }
 
+// Marshal encodes all the keys and values of List into a binary snapshot.
+// The format is: key count, then for every key the key length, key, element count, and every element prefixed by its length.
+// All the counts and lengths are encoded as 4 bytes big endian unsigned integers.
+func (lis *List) Marshal() ([]byte, error) {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	size := 4
+	for key, l := range lis.record {
+		size += 4 + len(key) + 4
+		if l != nil {
+			for e := l.Front(); e != nil; e = e.Next() {
+				size += 4 + len(valueOf(e))
+			}
+		}
+	}
+
+	buf := make([]byte, size)
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(lis.record)))
+	offset := 4
+	for key, l := range lis.record {
+		binary.BigEndian.PutUint32(buf[offset:offset+4], uint32(len(key)))
+		offset += 4
+		offset += copy(buf[offset:], key)
+
+		length := 0
+		if l != nil {
+			length = l.Len()
+		}
+		binary.BigEndian.PutUint32(buf[offset:offset+4], uint32(length))
+		offset += 4
+
+		if length == 0 {
+			continue
+		}
+		for e := l.Front(); e != nil; e = e.Next() {
+			value := valueOf(e)
+			binary.BigEndian.PutUint32(buf[offset:offset+4], uint32(len(value)))
+			offset += 4
+			offset += copy(buf[offset:], value)
+		}
+	}
+	return buf, nil
+}
+
+// Unmarshal decodes a binary snapshot created by Marshal, and replaces all the keys and values of List with it.
+// ErrInvalidData is returned if the data is malformed, and ErrValueTooLarge is returned if any value is larger than the max value size,
+// in both cases List is left untouched.
+func (lis *List) Unmarshal(data []byte) error {
+	offset := 0
+	readUint32 := func() (int, bool) {
+		if len(data)-offset < 4 {
+			return 0, false
+		}
+		n := binary.BigEndian.Uint32(data[offset : offset+4])
+		offset += 4
+		return int(n), true
+	}
+	readBytes := func() ([]byte, bool) {
+		n, ok := readUint32()
+		if !ok || len(data)-offset < n {
+			return nil, false
+		}
+		b := make([]byte, n)
+		offset += copy(b, data[offset:offset+n])
+		return b, true
+	}
+
+	keyCount, ok := readUint32()
+	if !ok {
+		return ErrInvalidData
+	}
+
+	record := make(Record)
+	for i := 0; i < keyCount; i++ {
+		key, ok := readBytes()
+		if !ok {
+			return ErrInvalidData
+		}
+		length, ok := readUint32()
+		if !ok {
+			return ErrInvalidData
+		}
+
+		l := list.New()
+		for j := 0; j < length; j++ {
+			value, ok := readBytes()
+			if !ok {
+				return ErrInvalidData
+			}
+			l.PushBack(value)
+		}
+		record[string(key)] = l
+	}
+	if offset != len(data) {
+		return ErrInvalidData
+	}
+
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	for _, l := range record {
+		for p := l.Front(); p != nil; p = p.Next() {
+			if lis.tooLarge(valueOf(p)) {
+				return ErrValueTooLarge
+			}
+		}
+	}
+	lis.record = record
+	lis.version = make(map[string]uint64, len(record))
+	lis.expires = make(map[string]int64)
+	lis.hints = make(map[string]findHint)
+	lis.notifyAll()
+	for key := range record {
+		lis.touch(key)
+	}
+	return nil
+}
+
+// Snapshot returns a copy of all the keys and values of List, which does not depend on the storage package.
+// The values are copied, so the snapshot and List can be modified independently.
+func (lis *List) Snapshot() map[string][][]byte {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	snapshot := make(map[string][][]byte, len(lis.record))
+	for key, item := range lis.record {
+		vals := make([][]byte, 0, lis.length(key))
+		if item != nil {
+			for p := item.Front(); p != nil; p = p.Next() {
+				vals = append(vals, copyBytes(valueOf(p)))
+			}
+		}
+		snapshot[key] = vals
+	}
+	return snapshot
+}
+
+// Restore replaces all the keys and values of List with the snapshot, usually created by Snapshot.
+// The values are copied, and the ttl of all the keys are removed.
+// It returns false and leaves List untouched if any value is larger than the max value size.
+func (lis *List) Restore(snapshot map[string][][]byte) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	for _, vals := range snapshot {
+		if lis.tooLarge(vals...) {
+			return false
+		}
+	}
+
+	record := make(Record, len(snapshot))
+	for key, vals := range snapshot {
+		l := list.New()
+		for _, v := range vals {
+			l.PushBack(copyBytes(v))
+		}
+		record[key] = l
+	}
+
+	lis.record = record
+	lis.version = make(map[string]uint64, len(record))
+	lis.expires = make(map[string]int64)
+	lis.hints = make(map[string]findHint)
+	lis.notifyAll()
+	for key := range record {
+		lis.touch(key)
+	}
+	return true
+}
+
+// SetMaxValueSize sets the max size of a value that can be added to List, 0 means unlimited, which is the default.
+// Operations that add or set values larger than n are rejected as a whole without changing the list,
+// pushes and inserts return the current length of the list, LSet and Restore return false, Unmarshal returns ErrValueTooLarge,
+// and other operations return their zero value.
+// Values already in List are not affected.
+func (lis *List) SetMaxValueSize(n int) {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if n < 0 {
+		n = 0
+	}
+	lis.maxValueSize = n
+}
+
+// LEnsureCapacity is a hint that about n elements are going to be added to the list stored at key.
+// Currently it only creates an empty list if key does not exist, because container/list can not preallocate its elements, n is ignored.
+// The backing of a key may be switched to a preallocated structure based on this hint in the future.
+func (lis *List) LEnsureCapacity(key string, n int) {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if _, ok := lis.record[key]; ok {
+		return
+	}
+	lis.record[key] = list.New()
+	lis.touch(key)
+}
+
 // LPush insert all the specified values at the head of the list stored at key.
 // If key does not exist, it is created as empty list before performing the push operations.
 func (lis *List) LPush(key string, val ...[]byte) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(val...) {
+		return lis.length(key)
+	}
+
+	return lis.push(true, key, val...)
+}
+
+// LPushBatch is the same as LPush(key, vals...), the values are pushed to the head one by one,
+// so the last value of vals becomes the first element of the list.
+func (lis *List) LPushBatch(key string, vals [][]byte) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(vals...) {
+		return lis.length(key)
+	}
+
+	return lis.push(true, key, vals...)
+}
+
+// LPopCount removes and returns up to count elements from the head of the list stored at key, in pop order.
+// If the list has fewer than count elements, all of them are returned and the key is left as an empty list, same as LPop.
+func (lis *List) LPopCount(key string, count int) [][]byte {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	return lis.popCount(true, key, count)
+}
+
+// LPopN removes and returns up to |count| elements of the list stored at key, in pop order.
+// A positive count pops elements from the head, and a negative count pops elements from the tail,
+// in which case the first returned element is the original tail of the list.
+// An empty slice is returned if count is 0 or key does not exist.
+func (lis *List) LPopN(key string, count int) [][]byte {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	var val [][]byte
+	if count < 0 {
+		val = lis.popCount(false, key, -count)
+	} else {
+		val = lis.popCount(true, key, count)
+	}
+	if val == nil {
+		val = [][]byte{}
+	}
+	return val
+}
+
+// LPopUntil removes and returns the elements from the head (fromFront is true) or tail of the list stored at key in pop order,
+// as long as pred returns true for them. The first element for which pred returns false is left in the list.
+// An empty slice is returned if the list is empty, key does not exist or pred is nil.
+// pred is called with the write lock held, so it must not call the methods of List.
+func (lis *List) LPopUntil(key string, fromFront bool, pred func(val []byte) bool) [][]byte {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	val := [][]byte{}
+	item := lis.record[key]
+	if item == nil || pred == nil {
+		return val
+	}
+
+	for item.Len() > 0 && pred(lis.peek(fromFront, key)) {
+		val = append(val, lis.pop(fromFront, key))
+	}
+	return val
+}
+
+// LPushX insert the specified values at the head of the list stored at key, only if key already exists.
+// In contrary to LPush, no operation will be performed when key does not yet exist, and 0 is returned.
+func (lis *List) LPushX(key string, val ...[]byte) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(val...) {
+		return lis.length(key)
+	}
+
+	if _, ok := lis.record[key]; !ok {
+		return 0
+	}
 	return lis.push(true, key, val...)
 }
 
+// LPushUnique insert val at the head of the list stored at key, only if no element equal to val exists in the list.
+// It returns the length of the list after the operation, which is unchanged if val was already present.
+// Note that the whole list is scanned, so the time complexity is O(N).
+func (lis *List) LPushUnique(key string, val []byte) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(val) {
+		return lis.length(key)
+	}
+
+	if lis.find(key, val) != nil {
+		return lis.record[key].Len()
+	}
+	return lis.push(true, key, val)
+}
+
+// LPushIfChanged insert val at the head of the list stored at key, only if it is not equal to the current head element.
+// It is useful to collapse consecutive duplicate values. If key does not exist, it is created.
+// It returns the length of the list after the operation, which is unchanged if val equals the head element.
+func (lis *List) LPushIfChanged(key string, val []byte) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(val) {
+		return lis.length(key)
+	}
+
+	item := lis.record[key]
+	if item != nil && item.Len() > 0 && reflect.DeepEqual(lis.peek(true, key), val) {
+		return item.Len()
+	}
+	return lis.push(true, key, val)
+}
+
 // LPop removes and returns the first elements of the list stored at key.
 func (lis *List) LPop(key string) []byte {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
 	return lis.pop(true, key)
 }
 
+// LPopE removes and returns the first element of the list stored at key.
+// Unlike LPop, ErrKeyNotFound is returned if key does not exist and ErrEmptyList is returned if the list is empty.
+func (lis *List) LPopE(key string) ([]byte, error) {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	return lis.popE(true, key)
+}
+
 // RPush insert all the specified values at the tail of the list stored at key.
 // If key does not exist, it is created as empty list before performing the push operation.
 func (lis *List) RPush(key string, val ...[]byte) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(val...) {
+		return lis.length(key)
+	}
+
+	return lis.push(false, key, val...)
+}
+
+// RPushBatch is the same as RPush(key, vals...), the values are pushed to the tail in order.
+func (lis *List) RPushBatch(key string, vals [][]byte) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(vals...) {
+		return lis.length(key)
+	}
+
+	return lis.push(false, key, vals...)
+}
+
+// RPopCount removes and returns up to count elements from the tail of the list stored at key, in pop order.
+// If the list has fewer than count elements, all of them are returned and the key is left as an empty list, same as RPop.
+func (lis *List) RPopCount(key string, count int) [][]byte {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	return lis.popCount(false, key, count)
+}
+
+// RPushX insert the specified values at the tail of the list stored at key, only if key already exists.
+// In contrary to RPush, no operation will be performed when key does not yet exist, and 0 is returned.
+func (lis *List) RPushX(key string, val ...[]byte) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(val...) {
+		return lis.length(key)
+	}
+
+	if _, ok := lis.record[key]; !ok {
+		return 0
+	}
 	return lis.push(false, key, val...)
 }
 
 // RPop removes and returns the last elements of the list stored at key.
 func (lis *List) RPop(key string) []byte {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
 	return lis.pop(false, key)
 }
 
+// LPopHeader removes the first element of the list stored at key, and returns copies of its first headerLen bytes and the rest of it.
+// If the element is shorter than headerLen, it is returned as the header with an empty body. nil, nil is returned if the list is empty.
+func (lis *List) LPopHeader(key string, headerLen int) (header, body []byte) {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.length(key) == 0 {
+		return nil, nil
+	}
+
+	val := lis.pop(true, key)
+	if headerLen < 0 {
+		headerLen = 0
+	}
+	if headerLen > len(val) {
+		headerLen = len(val)
+	}
+	header = append([]byte{}, val[:headerLen]...)
+	body = append([]byte{}, val[headerLen:]...)
+	return
+}
+
+// RPopE removes and returns the last element of the list stored at key.
+// Unlike RPop, ErrKeyNotFound is returned if key does not exist and ErrEmptyList is returned if the list is empty.
+func (lis *List) RPopE(key string) ([]byte, error) {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	return lis.popE(false, key)
+}
+
+// BLPop removes and returns the first element of the list stored at key, and blocks until an element is pushed if the list is empty or key does not exist.
+// ctx.Err() is returned if ctx is done before an element is available.
+func (lis *List) BLPop(ctx context.Context, key string) ([]byte, error) {
+	return lis.wait(ctx, key, func() ([]byte, bool) {
+		val, err := lis.LPopE(key)
+		return val, err == nil
+	})
+}
+
+// BRPop is the same as BLPop, but removes and returns the last element of the list.
+func (lis *List) BRPop(ctx context.Context, key string) ([]byte, error) {
+	return lis.wait(ctx, key, func() ([]byte, bool) {
+		val, err := lis.RPopE(key)
+		return val, err == nil
+	})
+}
+
+// LMove atomically removes the first/last element of the list stored at srcKey, and pushes the element at the first/last element of the list stored at dstKey.
+// srcFront and dstFront decide which side of the source and destination list is used, true means the head and false means the tail.
+// If srcKey and dstKey are the same, the operation is equivalent to rotating the list.
+// The moved element is returned, if the source list is empty nil is returned and the destination is left untouched.
+func (lis *List) LMove(srcKey, dstKey string, srcFront, dstFront bool) []byte {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	val, _ := lis.move(srcKey, dstKey, srcFront, dstFront)
+	return val
+}
+
+// BLMove is the same as LMove, but blocks until an element is pushed if the source list is empty or srcKey does not exist.
+// ctx.Err() is returned if ctx is done before an element is available.
+func (lis *List) BLMove(ctx context.Context, srcKey, dstKey string, srcFront, dstFront bool) ([]byte, error) {
+	return lis.wait(ctx, srcKey, func() ([]byte, bool) {
+		lis.mu.Lock()
+		defer lis.mu.Unlock()
+
+		return lis.move(srcKey, dstKey, srcFront, dstFront)
+	})
+}
+
+// LMoveByValue moves the first element equal to val to the head of the list stored at key if toFront is true, otherwise to the tail.
+// It returns false if no element is equal to val.
+func (lis *List) LMoveByValue(key string, val []byte, toFront bool) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	e := lis.find(key, val)
+	if e == nil {
+		return false
+	}
+
+	item := lis.record[key]
+	if toFront && e != item.Front() {
+		item.MoveToFront(e)
+		lis.touch(key)
+		lis.setHint(key, val, e)
+	}
+	if !toFront && e != item.Back() {
+		item.MoveToBack(e)
+		lis.touch(key)
+	}
+	return true
+}
+
+// LMoveN atomically moves up to count elements from the list stored at srcKey to the list stored at dstKey, see LMove.
+// The elements are moved one by one, so their relative order is preserved at the destination if srcFront != dstFront, and reversed otherwise.
+// count is clamped to the length of the source list before moving, so if srcKey and dstKey are the same, every element is moved at most once.
+// It returns the number of moved elements.
+func (lis *List) LMoveN(srcKey, dstKey string, srcFront, dstFront bool, count int) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if length := lis.length(srcKey); count > length {
+		count = length
+	}
+	n := 0
+	for ; n < count; n++ {
+		lis.push(dstFront, dstKey, lis.pop(srcFront, srcKey))
+	}
+	return n
+}
+
+// RPopLPush atomically removes the last element of the list stored at srcKey, and pushes the element at the first element of the list stored at dstKey.
+// If srcKey and dstKey are the same, the operation is equivalent to removing the last element from the list and pushing it as first element of the list, so it can be considered as a list rotation command.
+func (lis *List) RPopLPush(srcKey, dstKey string) []byte {
+	return lis.LMove(srcKey, dstKey, false, true)
+}
+
+// LMPop pops up to count elements from the first non-empty list in the given keys, scanned in order.
+// If front is true the elements are popped from the head of the list, otherwise from the tail.
+// It returns the key of the list the elements were popped from and the popped elements, or "" and nil if all lists are empty or count <= 0.
+func (lis *List) LMPop(front bool, count int, keys ...string) (string, [][]byte) {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if count <= 0 {
+		return "", nil
+	}
+	for _, key := range keys {
+		item := lis.record[key]
+		if item == nil || item.Len() <= 0 {
+			continue
+		}
+		return key, lis.popCount(front, key, count)
+	}
+	return "", nil
+}
+
 // LIndex returns the element at index index in the list stored at key.
 // The index is zero-based, so 0 means the first element, 1 the second element and so on.
 // Negative indices can be used to designate elements starting at the tail of the list. Here, -1 means the last element, -2 means the penultimate and so forth.
+// The returned value is the stored value, modifying it modifies the element in the list, use LIndexCopy to get a copy.
 func (lis *List) LIndex(key string, index int) []byte {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
 	ok, newIndex := lis.validIndex(key, index)
 	if !ok {
 		return nil
 	}
 
-	index = newIndex
-	var val []byte
-	e := lis.index(key, index)
-	if e != nil {
-		val = e.Value.([]byte)
+	index = newIndex
+	var val []byte
+	e := lis.index(key, index)
+	if e != nil {
+		val = valueOf(e)
+	}
+
+	return val
+}
+
+// LIndexMulti returns the elements at the given indices of the list stored at key, in the order of indices.
+// Negative indices are supported like LIndex, and nil is returned for every index that is out of range.
+// The list is traversed only once, no matter how many indices are given.
+func (lis *List) LIndexMulti(key string, indices ...int) [][]byte {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	res := make([][]byte, len(indices))
+	item := lis.record[key]
+	if item == nil || item.Len() == 0 {
+		return res
+	}
+
+	// positions maps every valid index to the positions of res it should be stored in.
+	positions := make(map[int][]int)
+	var sorted []int
+	for i, index := range indices {
+		ok, newIndex := lis.validIndex(key, index)
+		if !ok {
+			continue
+		}
+		if _, exist := positions[newIndex]; !exist {
+			sorted = append(sorted, newIndex)
+		}
+		positions[newIndex] = append(positions[newIndex], i)
+	}
+	sort.Ints(sorted)
+
+	p, flag := item.Front(), 0
+	for _, index := range sorted {
+		for ; flag < index; flag++ {
+			p = p.Next()
+		}
+		for _, i := range positions[index] {
+			res[i] = valueOf(p)
+		}
+	}
+	return res
+}
+
+// LIndexCopy is the same as LIndex, but returns a copy of the element, which can be safely modified.
+func (lis *List) LIndexCopy(key string, index int) []byte {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	e := lis.index(key, index)
+	if e == nil {
+		return nil
+	}
+	return copyBytes(valueOf(e))
+}
+
+// LPeek returns the first element of the list stored at key without removing it.
+// nil is returned if key does not exist or the list is empty.
+func (lis *List) LPeek(key string) []byte {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	return lis.peek(true, key)
+}
+
+// RPeek returns the last element of the list stored at key without removing it.
+// nil is returned if key does not exist or the list is empty.
+func (lis *List) RPeek(key string) []byte {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	return lis.peek(false, key)
+}
+
+// LPos returns the index of the first element equal to val in the list stored at key, scanning from head to tail.
+// If no matching element is found or key does not exist, -1 is returned.
+func (lis *List) LPos(key string, val []byte) int {
+	return lis.LPosRank(key, val, 1)
+}
+
+// LPosRank returns the index of the rank-th element equal to val in the list stored at key.
+// A positive rank scans from head to tail, 1 means the first match, 2 the second and so on.
+// A negative rank scans from tail to head, -1 means the last match, -2 the penultimate and so forth.
+// The returned index is always zero-based from the head. -1 is returned if there is no such element or rank is 0.
+func (lis *List) LPosRank(key string, val []byte, rank int) int {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	item := lis.record[key]
+	if item == nil || rank == 0 {
+		return -1
+	}
+
+	if rank > 0 {
+		i := 0
+		for p := item.Front(); p != nil; p, i = p.Next(), i+1 {
+			if reflect.DeepEqual(valueOf(p), val) {
+				if rank--; rank == 0 {
+					return i
+				}
+			}
+		}
+	} else {
+		i := item.Len() - 1
+		for p := item.Back(); p != nil; p, i = p.Prev(), i-1 {
+			if reflect.DeepEqual(valueOf(p), val) {
+				if rank++; rank == 0 {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// LPosCount returns the indexes of up to count elements equal to val in the list stored at key.
+// The count argument influences the operation in the following ways:
+// count > 0: Scan from head to tail and return at most count indexes.
+// count < 0: Scan from tail to head and return at most -count indexes, in the order they were found.
+// count = 0: Return the indexes of all matching elements from head to tail.
+func (lis *List) LPosCount(key string, val []byte, count int) []int {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	var res []int
+	item := lis.record[key]
+	if item == nil {
+		return res
+	}
+
+	if count >= 0 {
+		i := 0
+		for p := item.Front(); p != nil && (count == 0 || len(res) < count); p, i = p.Next(), i+1 {
+			if reflect.DeepEqual(valueOf(p), val) {
+				res = append(res, i)
+			}
+		}
+	} else {
+		i := item.Len() - 1
+		for p := item.Back(); p != nil && len(res) < -count; p, i = p.Prev(), i-1 {
+			if reflect.DeepEqual(valueOf(p), val) {
+				res = append(res, i)
+			}
+		}
+	}
+	return res
+}
+
+// LFindAll returns the indexes and values of all the elements equal to val in the list stored at key, from head to tail.
+// The two returned slices are parallel, and both are empty if there is no matching element.
+func (lis *List) LFindAll(key string, val []byte) ([]int, [][]byte) {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	indexes, values := []int{}, [][]byte{}
+	item := lis.record[key]
+	if item == nil {
+		return indexes, values
+	}
+
+	i := 0
+	for p := item.Front(); p != nil; p, i = p.Next(), i+1 {
+		if reflect.DeepEqual(valueOf(p), val) {
+			indexes = append(indexes, i)
+			values = append(values, valueOf(p))
+		}
+	}
+	return indexes, values
+}
+
+// LRem removes the first count occurrences of elements equal to element from the list stored at key.
+// The count argument influences the operation in the following ways:
+// count > 0: Remove elements equal to element moving from head to tail.
+// count < 0: Remove elements equal to element moving from tail to head.
+// count = 0: Remove all elements equal to element.
+func (lis *List) LRem(key string, val []byte, count int) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	item := lis.record[key]
+	if item == nil {
+		return 0
+	}
+
+	var ele []*list.Element
+	if count == 0 {
+		for p := item.Front(); p != nil; p = p.Next() {
+			if reflect.DeepEqual(valueOf(p), val) {
+				ele = append(ele, p)
+			}
+		}
+	}
+	if count > 0 {
+		for p := item.Front(); p != nil && len(ele) < count; p = p.Next() {
+			if reflect.DeepEqual(valueOf(p), val) {
+				ele = append(ele, p)
+			}
+		}
+	}
+	if count < 0 {
+		for p := item.Back(); p != nil && len(ele) < -count; p = p.Prev() {
+			if reflect.DeepEqual(valueOf(p), val) {
+				ele = append(ele, p)
+			}
+		}
+	}
+
+	for _, e := range ele {
+		item.Remove(e)
+	}
+	length := len(ele)
+	ele = nil
+
+	if length > 0 {
+		lis.touch(key)
+	}
+	return length
+}
+
+// LRemFirst removes the first element equal to val from the list stored at key, and returns whether an element was removed.
+func (lis *List) LRemFirst(key string, val []byte) bool {
+	return lis.LRem(key, val, 1) == 1
+}
+
+// LRemLast removes the last element equal to val from the list stored at key, and returns whether an element was removed.
+func (lis *List) LRemLast(key string, val []byte) bool {
+	return lis.LRem(key, val, -1) == 1
+}
+
+// LRemWhere removes the first count elements for which pred returns true from the list stored at key.
+// The count argument has the same meaning as LRem. It returns the number of removed elements, and 0 if pred is nil.
+// pred is called with the write lock held, so it must not call the methods of List.
+func (lis *List) LRemWhere(key string, pred func(val []byte) bool, count int) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	item := lis.record[key]
+	if item == nil || pred == nil {
+		return 0
+	}
+
+	var ele []*list.Element
+	if count == 0 {
+		for p := item.Front(); p != nil; p = p.Next() {
+			if pred(valueOf(p)) {
+				ele = append(ele, p)
+			}
+		}
+	}
+	if count > 0 {
+		for p := item.Front(); p != nil && len(ele) < count; p = p.Next() {
+			if pred(valueOf(p)) {
+				ele = append(ele, p)
+			}
+		}
+	}
+	if count < 0 {
+		for p := item.Back(); p != nil && len(ele) < -count; p = p.Prev() {
+			if pred(valueOf(p)) {
+				ele = append(ele, p)
+			}
+		}
+	}
+
+	for _, e := range ele {
+		item.Remove(e)
+	}
+	if len(ele) > 0 {
+		lis.touch(key)
+	}
+	return len(ele)
+}
+
+// LReplace replaces the first count occurrences of elements equal to oldVal with newVal in the list stored at key, positions are kept.
+// The count argument has the same meaning as LRem.
+// It returns the number of replaced elements, which is 0 if oldVal equals newVal since nothing changes.
+func (lis *List) LReplace(key string, oldVal, newVal []byte, count int) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(newVal) {
+		return 0
+	}
+
+	item := lis.record[key]
+	if item == nil || reflect.DeepEqual(oldVal, newVal) {
+		return 0
+	}
+
+	n := 0
+	if count >= 0 {
+		for p := item.Front(); p != nil && (count == 0 || n < count); p = p.Next() {
+			if reflect.DeepEqual(valueOf(p), oldVal) {
+				p.Value = newVal
+				n++
+			}
+		}
+	} else {
+		for p := item.Back(); p != nil && n < -count; p = p.Prev() {
+			if reflect.DeepEqual(valueOf(p), oldVal) {
+				p.Value = newVal
+				n++
+			}
+		}
+	}
+
+	if n > 0 {
+		lis.touch(key)
+	}
+	return n
+}
+
+// LRemValues removes the occurrences of any of the given values from the list stored at key in a single pass.
+// The count argument has the same meaning as LRem, and it is applied to every value separately,
+// so that at most |count| occurrences of each value are removed when count is not 0.
+// It returns the total number of removed elements.
+func (lis *List) LRemValues(key string, count int, vals ...[]byte) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	item := lis.record[key]
+	if item == nil || len(vals) == 0 {
+		return 0
+	}
+
+	limit := count
+	if limit < 0 {
+		limit = -limit
+	}
+	removed := make(map[string]int, len(vals))
+	for _, v := range vals {
+		removed[string(v)] = 0
+	}
+
+	var ele []*list.Element
+	match := func(p *list.Element) {
+		v := string(valueOf(p))
+		if n, ok := removed[v]; ok && (limit == 0 || n < limit) {
+			removed[v] = n + 1
+			ele = append(ele, p)
+		}
+	}
+	if count >= 0 {
+		for p := item.Front(); p != nil; p = p.Next() {
+			match(p)
+		}
+	} else {
+		for p := item.Back(); p != nil; p = p.Prev() {
+			match(p)
+		}
+	}
+
+	for _, e := range ele {
+		item.Remove(e)
+	}
+	if len(ele) > 0 {
+		lis.touch(key)
+	}
+	return len(ele)
+}
+
+// LDedup removes all but the first occurrence of each distinct element in the list stored at key, the order of the remaining elements is preserved.
+// It returns the number of removed elements.
+func (lis *List) LDedup(key string) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	item := lis.record[key]
+	if item == nil {
+		return 0
+	}
+
+	var ele []*list.Element
+	seen := make(map[string]struct{})
+	for p := item.Front(); p != nil; p = p.Next() {
+		v := string(valueOf(p))
+		if _, ok := seen[v]; ok {
+			ele = append(ele, p)
+		} else {
+			seen[v] = struct{}{}
+		}
+	}
+
+	for _, e := range ele {
+		item.Remove(e)
+	}
+	if len(ele) > 0 {
+		lis.touch(key)
+	}
+	return len(ele)
+}
+
+// LRemAt removes the element at index in the list stored at key and returns its value, negative indices are supported like LIndex.
+// If the list becomes empty, the key is removed, same as LTrim.
+// nil is returned if key does not exist or index is out of range.
+func (lis *List) LRemAt(key string, index int) []byte {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	e := lis.index(key, index)
+	if e == nil {
+		return nil
+	}
+
+	item := lis.record[key]
+	val := valueOf(e)
+	item.Remove(e)
+	if item.Len() == 0 {
+		lis.removeKey(key)
+	} else {
+		lis.touch(key)
+	}
+	return val
+}
+
+// LRemRange removes all the elements of the list stored at key between start and end, both inclusive.
+// start and end are handled the same as LRange. If the list becomes empty, the key is removed, same as LTrim.
+// It returns the number of removed elements.
+func (lis *List) LRemRange(key string, start, end int) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	return len(lis.remRange(key, start, end))
+}
+
+// LSpliceMove moves count elements of the list stored at key starting at index start to the position before index dest, keeping their order.
+// start is handled like LIndex, and count is clamped to the number of elements after start.
+// dest is an index of the list with the moved elements removed, a negative dest is counted from its tail,
+// and dest equal to its length moves the elements to the tail.
+// It returns false if start or dest is out of range or count <= 0.
+func (lis *List) LSpliceMove(key string, start, count, dest int) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	ok, start := lis.validIndex(key, start)
+	if !ok || count <= 0 {
+		return false
+	}
+
+	item := lis.record[key]
+	if count > item.Len()-start {
+		count = item.Len() - start
+	}
+	rest := item.Len() - count
+	if dest < 0 {
+		dest += rest
+	}
+	if dest < 0 || dest > rest {
+		return false
+	}
+
+	// find the moved elements and the element they will be moved before, which is nil for the tail.
+	var ele []*list.Element
+	var mark *list.Element
+	i, restIndex := 0, 0
+	for p := item.Front(); p != nil && (mark == nil || len(ele) < count); p, i = p.Next(), i+1 {
+		if i >= start && i < start+count {
+			ele = append(ele, p)
+			continue
+		}
+		if restIndex == dest {
+			mark = p
+		}
+		restIndex++
+	}
+
+	for _, e := range ele {
+		if mark != nil {
+			item.MoveBefore(e, mark)
+		} else {
+			item.MoveToBack(e)
+		}
+	}
+	lis.touch(key)
+	return true
+}
+
+// LSplice removes deleteCount elements of the list stored at key starting at index start, inserts vals at that position, and returns the removed elements.
+// A negative start designates elements starting at the tail of the list like LIndex, and start is clamped to [0, length of the list],
+// so that vals are appended to the tail if start is not less than the length. If key does not exist, it is created to hold vals.
+// If deleteCount is larger than the number of elements after start, all of them are removed.
+func (lis *List) LSplice(key string, start, deleteCount int, vals ...[]byte) [][]byte {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(vals...) {
+		return nil
+	}
+
+	length := 0
+	if lis.record[key] != nil {
+		length = lis.record[key].Len()
+	}
+	if start < 0 {
+		start += length
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > length {
+		start = length
+	}
+
+	if deleteCount > length-start {
+		deleteCount = length - start
+	}
+	var removed [][]byte
+	if deleteCount > 0 {
+		removed = lis.remRange(key, start, start+deleteCount-1)
+	}
+
+	if len(vals) > 0 {
+		item := lis.record[key]
+		if item == nil || start >= item.Len() {
+			lis.push(false, key, vals...)
+		} else {
+			e := lis.index(key, start)
+			for _, v := range vals {
+				item.InsertBefore(v, e)
+			}
+			lis.touch(key)
+		}
+	}
+	return removed
+}
+
+// LInsert inserts element in the list stored at key either before or after the reference value pivot.
+func (lis *List) LInsert(key string, option InsertOption, pivot, val []byte) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(val) {
+		return lis.length(key)
+	}
+
+	e := lis.find(key, pivot)
+	if e == nil {
+		return -1
+	}
+
+	item := lis.record[key]
+	if option == Before {
+		item.InsertBefore(val, e)
+	}
+	if option == After {
+		item.InsertAfter(val, e)
+	}
+
+	lis.touch(key)
+	// the pivot is still the first matching element unless an equal value was inserted.
+	if !reflect.DeepEqual(val, pivot) {
+		lis.setHint(key, pivot, e)
+	}
+	return item.Len()
+}
+
+// LInsertMany inserts all the values in the list stored at key either before or after the reference value pivot.
+// The pivot is only searched once, and the values are inserted contiguously in the given order.
+// It returns the length of the list after the insert operation, or -1 when the value pivot was not found.
+func (lis *List) LInsertMany(key string, option InsertOption, pivot []byte, vals ...[]byte) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(vals...) {
+		return lis.length(key)
+	}
+
+	e := lis.find(key, pivot)
+	if e == nil {
+		return -1
+	}
+
+	item := lis.record[key]
+	if option == Before {
+		for _, v := range vals {
+			item.InsertBefore(v, e)
+		}
+	}
+	if option == After {
+		for _, v := range vals {
+			e = item.InsertAfter(v, e)
+		}
+	}
+
+	lis.touch(key)
+	return item.Len()
+}
+
+// LInsertByIndex inserts element in the list stored at key either before or after the element at index, negative indices are supported like LIndex.
+// It returns the length of the list after the insert operation, or -1 when index is out of range.
+func (lis *List) LInsertByIndex(key string, index int, option InsertOption, val []byte) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(val) {
+		return lis.length(key)
+	}
+
+	e := lis.index(key, index)
+	if e == nil {
+		return -1
+	}
+
+	item := lis.record[key]
+	if option == Before {
+		item.InsertBefore(val, e)
+	}
+	if option == After {
+		item.InsertAfter(val, e)
+	}
+
+	lis.touch(key)
+	return item.Len()
+}
+
+// LInsertSorted inserts val into the list stored at key, which must be sorted by less, keeping it sorted.
+// val is inserted after the elements equal to it. If less is nil, the bytes are compared in ascending order.
+// If key does not exist, it is created as a list with only val.
+// It returns the index val was inserted at, -1 is returned if val is larger than the max value size.
+// less is called with the write lock held, so it must not call the methods of List.
+func (lis *List) LInsertSorted(key string, val []byte, less func(a, b []byte) bool) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(val) {
+		return -1
+	}
+	if less == nil {
+		less = func(a, b []byte) bool {
+			return bytes.Compare(a, b) < 0
+		}
+	}
+	if lis.record[key] == nil {
+		lis.record[key] = list.New()
+	}
+
+	item := lis.record[key]
+	index := 0
+	p := item.Front()
+	for ; p != nil && !less(val, valueOf(p)); p = p.Next() {
+		index++
+	}
+	if p == nil {
+		item.PushBack(val)
+	} else {
+		item.InsertBefore(val, p)
+	}
+	lis.touch(key)
+	return index
+}
+
+// LSet sets the list element at index to element.
+func (lis *List) LSet(key string, index int, val []byte) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(val) {
+		return false
+	}
+
+	e := lis.index(key, index)
+	if e == nil {
+		return false
+	}
+
+	e.Value = val
+	lis.touch(key)
+	return true
+}
+
+// LGetSet sets the element at index of the list stored at key to val, and returns the old value atomically.
+// Negative index is supported like LIndex. nil is returned and the list is unchanged if index is out of range.
+func (lis *List) LGetSet(key string, index int, val []byte) []byte {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(val) {
+		return nil
+	}
+
+	e := lis.index(key, index)
+	if e == nil {
+		return nil
+	}
+
+	old := valueOf(e)
+	e.Value = val
+	lis.touch(key)
+	return old
+}
+
+// LReplaceAt sets the element at index of the list stored at key to newVal only if its current value is equal to expected.
+// Negative index is supported like LIndex. It returns false if index is out of range or the current value is not equal to expected.
+func (lis *List) LReplaceAt(key string, index int, expected, newVal []byte) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(newVal) {
+		return false
+	}
+
+	e := lis.index(key, index)
+	if e == nil || !reflect.DeepEqual(valueOf(e), expected) {
+		return false
+	}
+
+	e.Value = newVal
+	lis.touch(key)
+	return true
+}
+
+// LApplyAt replaces the element at index of the list stored at key with the result of fn called with the old value, atomically.
+// Negative index is supported like LIndex. If fn returns nil the element is set to an empty slice, not removed.
+// It returns false if index is out of range, fn is nil or the new value is larger than the max value size.
+// fn is called with the write lock held, so it must not call the methods of List.
+func (lis *List) LApplyAt(key string, index int, fn func(old []byte) []byte) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if fn == nil {
+		return false
+	}
+	e := lis.index(key, index)
+	if e == nil {
+		return false
+	}
+
+	val := fn(valueOf(e))
+	if val == nil {
+		val = []byte{}
+	}
+	if lis.tooLarge(val) {
+		return false
+	}
+	e.Value = val
+	lis.touch(key)
+	return true
+}
+
+// LSetRange overwrites the elements of the list stored at key starting at index start with the given values.
+// Negative start is supported like LIndex. The list never grows, writing stops at the tail of the list.
+// It returns the number of elements written, 0 is returned if key does not exist or start is out of range.
+func (lis *List) LSetRange(key string, start int, vals [][]byte) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(vals...) {
+		return 0
+	}
+
+	n := 0
+	for p := lis.index(key, start); p != nil && n < len(vals); p = p.Next() {
+		p.Value = vals[n]
+		n++
+	}
+	if n > 0 {
+		lis.touch(key)
+	}
+	return n
+}
+
+// LSwap swaps the elements at index i and j in the list stored at key, negative indices are supported like LIndex.
+// It returns false if key does not exist or either index is out of range.
+func (lis *List) LSwap(key string, i, j int) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	ei, ej := lis.index(key, i), lis.index(key, j)
+	if ei == nil || ej == nil {
+		return false
+	}
+
+	ei.Value, ej.Value = ej.Value, ei.Value
+	lis.touch(key)
+	return true
+}
+
+// LRange returns the specified elements of the list stored at key.
+// The offsets start and stop are zero-based indexes, with 0 being the first element of the list (the head of the list), 1 being the next element and so on.
+// These offsets can also be negative numbers indicating offsets starting at the end of the list.
+// For example, -1 is the last element of the list, -2 the penultimate, and so on.
+// The returned values are the stored values, modifying them modifies the elements in the list, use LRangeCopy to get copies.
+func (lis *List) LRange(key string, start, end int) [][]byte {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	return lis.rangeStep(key, start, end, 1)
+}
+
+// LRangeReverse is the same as LRange, but returns the elements from tail to head.
+func (lis *List) LRangeReverse(key string, start, end int) [][]byte {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	return lis.rangeFunc(key, start, end, 1, true, nil)
+}
+
+// LRangeFunc is the same as LRange, but applies mapFn to every element in the range and returns the results.
+// mapFn receives the stored value and must not modify it, it should return a new slice instead.
+// A nil mapFn makes LRangeFunc behave like LRange.
+// mapFn is called with the read lock held, so it must not call the methods of List.
+func (lis *List) LRangeFunc(key string, start, end int, mapFn func(val []byte) []byte) [][]byte {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	return lis.rangeFunc(key, start, end, 1, false, mapFn)
+}
+
+// LRangeCopy is the same as LRange, but returns copies of the elements, which can be safely modified.
+func (lis *List) LRangeCopy(key string, start, end int) [][]byte {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	val := lis.rangeStep(key, start, end, 1)
+	for i := range val {
+		val[i] = copyBytes(val[i])
+	}
+	return val
+}
+
+// LRangeStep returns every step-th element of the list stored at key between start and end, both inclusive.
+// start and end are handled the same as LRange, and a step of 1 is equivalent to LRange.
+// nil is returned if step <= 0.
+func (lis *List) LRangeStep(key string, start, end, step int) [][]byte {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	if step <= 0 {
+		return nil
+	}
+	return lis.rangeStep(key, start, end, step)
+}
+
+// LHead returns the first n elements of the list stored at key, from head to tail.
+// If the list has fewer than n elements, all of them are returned. An empty slice is returned if n <= 0 or key does not exist.
+func (lis *List) LHead(key string, n int) [][]byte {
+	if n <= 0 {
+		return [][]byte{}
+	}
+	if val := lis.LRange(key, 0, n-1); val != nil {
+		return val
+	}
+	return [][]byte{}
+}
+
+// LTail returns the last n elements of the list stored at key, from head to tail.
+// If the list has fewer than n elements, all of them are returned. An empty slice is returned if n <= 0 or key does not exist.
+func (lis *List) LTail(key string, n int) [][]byte {
+	if n <= 0 {
+		return [][]byte{}
+	}
+	if val := lis.LRange(key, -n, -1); val != nil {
+		return val
+	}
+	return [][]byte{}
+}
+
+// LTrim trim an existing list so that it will contain only the specified range of elements specified.
+// Both start and stop are zero-based indexes, where 0 is the first element of the list (the head), 1 the next element and so on.
+// If the specified range is empty, the key is removed, same as LClear.
+func (lis *List) LTrim(key string, start, end int) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	return lis.trim(key, start, end)
+}
+
+// LTrimFront trim the list stored at key so that only the first n elements are kept.
+// If n <= 0 the list is emptied and the key is removed. It returns whether the list was changed.
+func (lis *List) LTrimFront(key string, n int) bool {
+	if n <= 0 {
+		return lis.LTrim(key, 1, 0)
+	}
+	return lis.LTrim(key, 0, n-1)
+}
+
+// LTrimBack trim the list stored at key so that only the last n elements are kept.
+// If n <= 0 the list is emptied and the key is removed. It returns whether the list was changed.
+func (lis *List) LTrimBack(key string, n int) bool {
+	if n <= 0 {
+		return lis.LTrim(key, 1, 0)
+	}
+	return lis.LTrim(key, -n, -1)
+}
+
+// LPushCapped insert all the specified values at the head of the list stored at key, and then trim the list to at most maxLen elements by dropping elements from the tail.
+// It returns the length of the list after the operation, if maxLen <= 0 nothing is pushed and the current length is returned.
+func (lis *List) LPushCapped(key string, maxLen int, val ...[]byte) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(val...) {
+		return lis.length(key)
+	}
+
+	if maxLen <= 0 {
+		if lis.record[key] == nil {
+			return 0
+		}
+		return lis.record[key].Len()
+	}
+
+	lis.push(true, key, val...)
+	lis.trim(key, 0, maxLen-1)
+	return lis.record[key].Len()
+}
+
+// LTruncateBytes removes elements from the tail of the list stored at key until the total length of its values is <= maxBytes.
+// The total length is counted like LBytesTotal. If the head element alone is larger than maxBytes, it is removed too,
+// and the key is removed when the list becomes empty. It returns the number of removed elements.
+func (lis *List) LTruncateBytes(key string, maxBytes int64) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	item := lis.record[key]
+	if item == nil {
+		return 0
+	}
+
+	total := int64(bytesTotal(item))
+	removed := 0
+	for total > maxBytes && item.Len() > 0 {
+		total -= int64(len(valueOf(item.Back())))
+		item.Remove(item.Back())
+		removed++
+	}
+
+	if item.Len() == 0 {
+		lis.removeKey(key)
+	} else if removed > 0 {
+		lis.touch(key)
+	}
+	return removed
+}
+
+// LReverse reverses the order of the elements of the list stored at key in place.
+// It returns false if key does not exist or the list is empty.
+func (lis *List) LReverse(key string) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	item := lis.record[key]
+	if item == nil || item.Len() <= 0 {
+		return false
+	}
+
+	for p := item.Front().Next(); p != nil; {
+		next := p.Next()
+		item.MoveToFront(p)
+		p = next
+	}
+	lis.touch(key)
+	return true
+}
+
+// LRotate rotates the list stored at key by n elements.
+// A positive n moves n elements from the head to the tail, a negative n moves -n elements from the tail to the head.
+// n is taken modulo the length of the list, so large values wrap around.
+// It returns false if key does not exist or the list is empty.
+func (lis *List) LRotate(key string, n int) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	item := lis.record[key]
+	if item == nil || item.Len() <= 0 {
+		return false
+	}
+
+	front := n > 0
+	if n < 0 {
+		n = -n
+	}
+	n %= item.Len()
+
+	for i := 0; i < n; i++ {
+		lis.push(!front, key, lis.pop(front, key))
+	}
+	return true
+}
+
+// LSort sorts the elements of the list stored at key in lexicographical order of the bytes.
+// The elements are sorted in ascending order, or in descending order if desc is true.
+// It returns false if key does not exist.
+func (lis *List) LSort(key string, desc bool) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	item, ok := lis.record[key]
+	if !ok {
+		return false
+	}
+
+	lis.sort(item, func(a, b []byte) bool {
+		if desc {
+			return bytes.Compare(a, b) > 0
+		}
+		return bytes.Compare(a, b) < 0
+	})
+	lis.touch(key)
+	return true
+}
+
+// LSortFunc sorts the elements of the list stored at key using the given less function.
+// The sort keeps the original order of equal elements, but the result is only well defined if less is a strict weak ordering.
+// It returns false if key does not exist or less is nil, in which case the list is left untouched.
+// less is called with the write lock held, so it must not call the methods of List.
+func (lis *List) LSortFunc(key string, less func(a, b []byte) bool) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	item, ok := lis.record[key]
+	if !ok || less == nil {
+		return false
+	}
+
+	lis.sort(item, less)
+	lis.touch(key)
+	return true
+}
+
+// LShuffle randomly permutes the elements of the list stored at key, using a random source seeded with seed.
+// The same seed always produces the same permutation for the same list, and a seed of 0 means using the current time as the seed.
+// It returns false if key does not exist.
+func (lis *List) LShuffle(key string, seed int64) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	item, ok := lis.record[key]
+	if !ok {
+		return false
+	}
+	if item == nil || item.Len() <= 1 {
+		return true
+	}
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	vals := make([]interface{}, 0, item.Len())
+	for p := item.Front(); p != nil; p = p.Next() {
+		vals = append(vals, p.Value)
+	}
+	rand.New(rand.NewSource(seed)).Shuffle(len(vals), func(i, j int) {
+		vals[i], vals[j] = vals[j], vals[i]
+	})
+
+	i := 0
+	for p := item.Front(); p != nil; p, i = p.Next(), i+1 {
+		p.Value = vals[i]
+	}
+	lis.touch(key)
+	return true
+}
+
+// LSampleRandom returns n distinct elements chosen randomly from the list stored at key without removing them.
+// The returned values are copies, and they are in random order. If n >= the length of the list, all the elements are returned shuffled.
+// The random source is seeded like LShuffle, so the same seed always returns the same sample for the same list.
+// nil is returned if key does not exist or n <= 0.
+func (lis *List) LSampleRandom(key string, n int, seed int64) [][]byte {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	item := lis.record[key]
+	if item == nil || item.Len() == 0 || n <= 0 {
+		return nil
+	}
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	vals := make([][]byte, 0, item.Len())
+	for p := item.Front(); p != nil; p = p.Next() {
+		vals = append(vals, valueOf(p))
+	}
+	if n > len(vals) {
+		n = len(vals)
+	}
+
+	res := make([][]byte, 0, n)
+	for _, i := range rand.New(rand.NewSource(seed)).Perm(len(vals))[:n] {
+		res = append(res, copyBytes(vals[i]))
+	}
+	return res
+}
+
+// LRenameKey renames oldKey to newKey without copying the elements, newKey is overwritten if it already exists.
+// The version and the expiration time of oldKey are moved to newKey too. This is the only case in which the version of a key
+// can decrease, if newKey existed with a larger version than oldKey, so a version of newKey taken before the rename
+// must not be compared with the ones after it.
+// It returns false if oldKey does not exist, renaming a key to itself does nothing and returns true.
+func (lis *List) LRenameKey(oldKey, newKey string) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	item, ok := lis.record[oldKey]
+	if !ok {
+		return false
+	}
+	if oldKey == newKey {
+		return true
+	}
+
+	version := lis.version[oldKey]
+	expire, hasExpire := lis.expires[oldKey]
+	lis.removeKey(oldKey)
+	lis.removeKey(newKey)
+
+	lis.record[newKey] = item
+	lis.version[newKey] = version
+	if hasExpire {
+		lis.expires[newKey] = expire
+	}
+	return true
+}
+
+// LClone copies the list stored at srcKey to dstKey, overwriting dstKey if it already exists.
+// The values are copied too, so the two lists can be modified independently. The ttl of dstKey is removed.
+// It returns false if srcKey does not exist.
+func (lis *List) LClone(srcKey, dstKey string) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	item, ok := lis.record[srcKey]
+	if !ok {
+		return false
+	}
+
+	newList := list.New()
+	if item != nil {
+		for p := item.Front(); p != nil; p = p.Next() {
+			newList.PushBack(copyBytes(valueOf(p)))
+		}
+	}
+	lis.record[dstKey] = newList
+	delete(lis.expires, dstKey)
+	lis.touch(dstKey)
+	return true
+}
+
+// LConcatMove moves all the elements of the list stored at srcKey to the tail of the list stored at dstKey, and removes srcKey.
+// The values are not copied. If dstKey does not exist, the list of srcKey is simply moved to dstKey.
+// It returns the length of the list stored at dstKey after the operation, if srcKey and dstKey are the same nothing is done.
+func (lis *List) LConcatMove(dstKey, srcKey string) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	src, dst := lis.record[srcKey], lis.record[dstKey]
+	if srcKey != dstKey && src != nil {
+		if dst == nil {
+			dst = src
+			lis.record[dstKey] = dst
+		} else {
+			// container/list can not splice two lists, so the elements are pushed one by one.
+			dst.PushBackList(src)
+		}
+		lis.removeKey(srcKey)
+		lis.touch(dstKey)
+	}
+
+	if dst == nil {
+		return 0
+	}
+	return dst.Len()
+}
+
+// LFlattenInto appends all the elements of the lists stored at srcKeys, in order, to the tail of the list stored at dstKey.
+// Missing source keys are treated as empty lists. The source lists are left unchanged and the values are copied.
+// It returns the length of the list stored at dstKey after the operation.
+func (lis *List) LFlattenInto(dstKey string, srcKeys ...string) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	var vals [][]byte
+	for _, k := range srcKeys {
+		item := lis.record[k]
+		if item == nil {
+			continue
+		}
+		for p := item.Front(); p != nil; p = p.Next() {
+			vals = append(vals, copyBytes(valueOf(p)))
+		}
+	}
+
+	if len(vals) == 0 {
+		return lis.length(dstKey)
+	}
+	return lis.push(false, dstKey, vals...)
+}
+
+// LInterleave stores in dstKey the elements of the lists stored at aKey and bKey taken alternately from head to tail, starting with aKey.
+// When one list runs out of elements, the rest of the other one are appended. Missing keys are treated as empty lists.
+// The source lists are left unchanged and the values are copied. dstKey is overwritten, and removed if the result is empty.
+// It returns the length of the list stored at dstKey.
+func (lis *List) LInterleave(dstKey, aKey, bKey string) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	var pa, pb *list.Element
+	if lis.record[aKey] != nil {
+		pa = lis.record[aKey].Front()
+	}
+	if lis.record[bKey] != nil {
+		pb = lis.record[bKey].Front()
+	}
+
+	newList := list.New()
+	for pa != nil || pb != nil {
+		if pa != nil {
+			newList.PushBack(copyBytes(valueOf(pa)))
+			pa = pa.Next()
+		}
+		if pb != nil {
+			newList.PushBack(copyBytes(valueOf(pb)))
+			pb = pb.Next()
+		}
+	}
+	lis.setList(dstKey, newList)
+	return newList.Len()
+}
+
+// LPartition splits the elements of the list stored at srcKey into two lists in one pass, the elements for which pred returns true
+// are stored in trueKey and the rest in falseKey, both keeping their relative order.
+// The source list is left unchanged and the values are copied. A missing srcKey is treated as an empty list.
+// trueKey and falseKey are overwritten, and removed if their result is empty.
+// It returns the lengths of the lists stored at trueKey and falseKey, nothing is done if pred is nil.
+// pred is called with the write lock held, so it must not call the methods of List.
+func (lis *List) LPartition(srcKey, trueKey, falseKey string, pred func(val []byte) bool) (int, int) {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if pred == nil {
+		return 0, 0
+	}
+
+	trueList, falseList := list.New(), list.New()
+	if item := lis.record[srcKey]; item != nil {
+		for p := item.Front(); p != nil; p = p.Next() {
+			val := valueOf(p)
+			if pred(val) {
+				trueList.PushBack(copyBytes(val))
+			} else {
+				falseList.PushBack(copyBytes(val))
+			}
+		}
+	}
+	lis.setList(trueKey, trueList)
+	lis.setList(falseKey, falseList)
+	return trueList.Len(), falseList.Len()
+}
+
+// LDiffInto stores in dstKey the elements of the list stored at aKey which are not equal to any element of the list stored at bKey.
+// The order and the duplicates of aKey are preserved. Missing keys are treated as empty lists.
+// The source lists are left unchanged and the values are copied. dstKey is overwritten, and removed if the result is empty.
+// It returns the length of the list stored at dstKey.
+func (lis *List) LDiffInto(dstKey, aKey, bKey string) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	exclude := make(map[string]struct{})
+	if item := lis.record[bKey]; item != nil {
+		for p := item.Front(); p != nil; p = p.Next() {
+			exclude[string(valueOf(p))] = struct{}{}
+		}
+	}
+
+	newList := list.New()
+	if item := lis.record[aKey]; item != nil {
+		for p := item.Front(); p != nil; p = p.Next() {
+			if _, ok := exclude[string(valueOf(p))]; !ok {
+				newList.PushBack(copyBytes(valueOf(p)))
+			}
+		}
+	}
+	lis.setList(dstKey, newList)
+	return newList.Len()
+}
+
+// LIntersectInto stores in dstKey the elements of the list stored at aKey which are equal to an element of the list stored at bKey.
+// The order of aKey is preserved, and a value is kept at most as many times as it appears in bKey. Missing keys are treated as empty lists.
+// The source lists are left unchanged and the values are copied. dstKey is overwritten, and removed if the result is empty.
+// It returns the length of the list stored at dstKey.
+func (lis *List) LIntersectInto(dstKey, aKey, bKey string) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	counts := make(map[string]int)
+	if item := lis.record[bKey]; item != nil {
+		for p := item.Front(); p != nil; p = p.Next() {
+			counts[string(valueOf(p))]++
+		}
+	}
+
+	newList := list.New()
+	if item := lis.record[aKey]; item != nil {
+		for p := item.Front(); p != nil; p = p.Next() {
+			v := string(valueOf(p))
+			if counts[v] > 0 {
+				counts[v]--
+				newList.PushBack(copyBytes(valueOf(p)))
+			}
+		}
+	}
+	lis.setList(dstKey, newList)
+	return newList.Len()
+}
+
+// LUnionInto stores in dstKey the distinct elements of the lists stored at aKey and bKey, in the order they are first seen
+// when walking aKey and then bKey. Missing keys are treated as empty lists.
+// The source lists are left unchanged and the values are copied. dstKey is overwritten, and removed if the result is empty.
+// It returns the length of the list stored at dstKey.
+func (lis *List) LUnionInto(dstKey, aKey, bKey string) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	newList := list.New()
+	seen := make(map[string]struct{})
+	for _, k := range []string{aKey, bKey} {
+		item := lis.record[k]
+		if item == nil {
+			continue
+		}
+		for p := item.Front(); p != nil; p = p.Next() {
+			v := string(valueOf(p))
+			if _, ok := seen[v]; !ok {
+				seen[v] = struct{}{}
+				newList.PushBack(copyBytes(valueOf(p)))
+			}
+		}
+	}
+	lis.setList(dstKey, newList)
+	return newList.Len()
+}
+
+// LMergeSortedInto merges the lists stored at aKey and bKey, which are both sorted by less, and stores the sorted result in dstKey.
+// The merge is stable, elements of aKey come first when elements compare equal. If less is nil, the bytes are compared in ascending order.
+// The source lists are left unchanged and the values are copied. dstKey is overwritten, and removed if the result is empty.
+// It returns the length of the list stored at dstKey.
+// less is called with the write lock held, so it must not call the methods of List.
+func (lis *List) LMergeSortedInto(dstKey, aKey, bKey string, less func(a, b []byte) bool) int {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if less == nil {
+		less = func(a, b []byte) bool {
+			return bytes.Compare(a, b) < 0
+		}
+	}
+
+	var pa, pb *list.Element
+	if lis.record[aKey] != nil {
+		pa = lis.record[aKey].Front()
+	}
+	if lis.record[bKey] != nil {
+		pb = lis.record[bKey].Front()
+	}
+
+	newList := list.New()
+	for pa != nil || pb != nil {
+		if pa == nil || (pb != nil && less(valueOf(pb), valueOf(pa))) {
+			newList.PushBack(copyBytes(valueOf(pb)))
+			pb = pb.Next()
+		} else {
+			newList.PushBack(copyBytes(valueOf(pa)))
+			pa = pa.Next()
+		}
+	}
+	lis.setList(dstKey, newList)
+	return newList.Len()
+}
+
+// LToSlice returns copies of all the elements of the list stored at key, from head to tail.
+func (lis *List) LToSlice(key string) [][]byte {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	var val [][]byte
+	item := lis.record[key]
+	if item == nil {
+		return val
+	}
+
+	val = make([][]byte, 0, item.Len())
+	for p := item.Front(); p != nil; p = p.Next() {
+		val = append(val, copyBytes(valueOf(p)))
+	}
+	return val
+}
+
+// LFromSlice replaces the list stored at key with the given values in order.
+// If key does not exist, it is created. The ttl of key is removed.
+func (lis *List) LFromSlice(key string, vals [][]byte) {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(vals...) {
+		return
+	}
+
+	newList := list.New()
+	for _, v := range vals {
+		newList.PushBack(v)
+	}
+	lis.record[key] = newList
+	delete(lis.expires, key)
+	lis.touch(key)
+}
+
+// LEach calls fn for every element of the list stored at key from head to tail, with the index and value of the element.
+// The iteration stops when fn returns false. The value passed to fn is the stored value, so it must not be modified.
+// fn is called with the read lock held, so it must not call the methods of List. Nothing is done if fn is nil.
+func (lis *List) LEach(key string, fn func(index int, val []byte) bool) {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	item := lis.record[key]
+	if item == nil || fn == nil {
+		return
+	}
+
+	i := 0
+	for p := item.Front(); p != nil; p, i = p.Next(), i+1 {
+		if !fn(i, valueOf(p)) {
+			return
+		}
+	}
+}
+
+// LEachReverse is the same as LEach, but iterates the list stored at key from tail to head.
+// The index passed to fn is still counted from the head, same as LIndex.
+// fn is called with the read lock held, so it must not call the methods of List. Nothing is done if fn is nil.
+func (lis *List) LEachReverse(key string, fn func(index int, val []byte) bool) {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	item := lis.record[key]
+	if item == nil || fn == nil {
+		return
+	}
+
+	i := item.Len() - 1
+	for p := item.Back(); p != nil; p, i = p.Prev(), i-1 {
+		if !fn(i, valueOf(p)) {
+			return
+		}
+	}
+}
+
+// Iterator returns an iterator over the elements of the list stored at key, from head to tail.
+// The iterator is read-only, if the list is modified during the iteration, which elements will be visited is undefined.
+func (lis *List) Iterator(key string) *ListIterator {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	return &ListIterator{lis: lis, item: lis.record[key]}
+}
+
+// Next moves the iterator to the next element, it returns false when there are no more elements.
+func (it *ListIterator) Next() bool {
+	it.lis.mu.RLock()
+	defer it.lis.mu.RUnlock()
+
+	if it.item == nil {
+		return false
+	}
+	if !it.started {
+		it.e = it.item.Front()
+		it.started = true
+	} else if it.e != nil {
+		it.e = it.e.Next()
+	}
+	return it.e != nil
+}
+
+// Value returns the value of the current element, nil is returned if the iterator is not positioned at an element.
+func (it *ListIterator) Value() []byte {
+	it.lis.mu.RLock()
+	defer it.lis.mu.RUnlock()
+
+	if it.e == nil {
+		return nil
+	}
+	return valueOf(it.e)
+}
+
+// LLen returns the length of the list stored at key.
+// If key does not exist, it is interpreted as an empty list and 0 is returned.
+func (lis *List) LLen(key string) int {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	length := 0
+	if lis.record[key] != nil {
+		length = lis.record[key].Len()
+	}
+
+	return length
+}
+
+// LLenMulti returns the length of the lists stored at the given keys.
+// Every requested key is in the returned map, and the length of a key that does not exist is 0.
+func (lis *List) LLenMulti(keys ...string) map[string]int {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	res := make(map[string]int, len(keys))
+	for _, key := range keys {
+		res[key] = 0
+		if lis.record[key] != nil {
+			res[key] = lis.record[key].Len()
+		}
+	}
+	return res
+}
+
+// LMemUsage returns the approximate memory in bytes used by the list stored at key.
+// It is the sum of the length of all values plus NodeOverhead for every element, 0 is returned if key does not exist.
+func (lis *List) LMemUsage(key string) int64 {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	item := lis.record[key]
+	if item == nil {
+		return 0
+	}
+
+	return int64(bytesTotal(item)) + int64(item.Len())*NodeOverhead
+}
+
+// LBytesTotal returns the total length of all the values of the list stored at key, without the overhead counted by LMemUsage.
+// 0 is returned if key does not exist.
+func (lis *List) LBytesTotal(key string) int {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	return bytesTotal(lis.record[key])
+}
+
+// LCompact rebuilds the list stored at key into a new list with the same elements, and drops the old one so it can be reclaimed by the GC.
+// The content and the version of the list are unchanged, but iterators created before will keep walking the old list.
+// It returns false if key does not exist.
+func (lis *List) LCompact(key string) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	item, ok := lis.record[key]
+	if !ok {
+		return false
+	}
+	if item == nil {
+		return true
+	}
+
+	newList := list.New()
+	for p := item.Front(); p != nil; p = p.Next() {
+		newList.PushBack(p.Value)
+	}
+	lis.record[key] = newList
+	delete(lis.hints, key)
+	return true
+}
+
+// LDrain returns all the elements of the list stored at key from head to tail, and removes the key atomically.
+// An empty slice is returned if key does not exist.
+func (lis *List) LDrain(key string) [][]byte {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	item, ok := lis.record[key]
+	if !ok {
+		return [][]byte{}
+	}
+
+	vals := [][]byte{}
+	if item != nil {
+		vals = make([][]byte, 0, item.Len())
+		for p := item.Front(); p != nil; p = p.Next() {
+			vals = append(vals, valueOf(p))
+		}
+	}
+	lis.removeKey(key)
+	return vals
+}
+
+// LClear clear a specified key for List.
+func (lis *List) LClear(key string) {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	lis.removeKey(key)
+}
+
+// LClearAll removes all the keys of List, and their ttl and versions.
+// The versions assigned afterwards are still larger than the removed ones, so they are never reused.
+func (lis *List) LClearAll() {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	lis.record = make(Record)
+	lis.version = make(map[string]uint64)
+	lis.expires = make(map[string]int64)
+	lis.hints = make(map[string]findHint)
+	lis.notifyAll()
+}
+
+// LValidate removes all the keys whose list is nil, and returns the removed keys.
+func (lis *List) LValidate() []string {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	var keys []string
+	for key, l := range lis.record {
+		if l == nil {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		lis.removeKey(key)
+	}
+	return keys
+}
+
+// LVersion returns the version of the list stored at key, which changes on every mutation of the list.
+// Versions of a key are monotonically increasing, and 0 is returned if key does not exist.
+func (lis *List) LVersion(key string) uint64 {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	return lis.version[key]
+}
+
+// LSetIfVersion sets the list element at index to element, only if the version of the list stored at key equals expected.
+// It returns false if the version doesn't match or index is out of range.
+func (lis *List) LSetIfVersion(key string, index int, val []byte, expected uint64) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if lis.tooLarge(val) {
+		return false
+	}
+
+	if lis.version[key] != expected {
+		return false
+	}
+
+	e := lis.index(key, index)
+	if e == nil {
+		return false
+	}
+
+	e.Value = val
+	lis.touch(key)
+	return true
+}
+
+// LWatch returns a channel which receives a signal every time the list stored at key is changed or removed, and a function to stop watching.
+// Signals are coalesced, the channel holds at most one pending signal, and it is sent after the change is made.
+// The channel is not closed by the cancel function, which can be called multiple times.
+func (lis *List) LWatch(key string) (<-chan struct{}, func()) {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	ch := make(chan struct{}, 1)
+	if lis.watchers[key] == nil {
+		lis.watchers[key] = make(map[chan struct{}]struct{})
+	}
+	lis.watchers[key][ch] = struct{}{}
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			lis.mu.Lock()
+			defer lis.mu.Unlock()
+
+			delete(lis.watchers[key], ch)
+			if len(lis.watchers[key]) == 0 {
+				delete(lis.watchers, key)
+			}
+		})
+	}
+	return ch, cancel
+}
+
+// LSetTTL sets the expiration time of the list stored at key, as unix time in nanoseconds.
+// The key will be removed by LExpireSweep after it expires. It returns false if key does not exist.
+func (lis *List) LSetTTL(key string, unixNano int64) bool {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	if _, ok := lis.record[key]; !ok {
+		return false
+	}
+	lis.expires[key] = unixNano
+	return true
+}
+
+// LTTL returns the expiration time of the list stored at key as unix time in nanoseconds.
+// false is returned if key does not exist or has no ttl.
+func (lis *List) LTTL(key string) (int64, bool) {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	deadline, ok := lis.expires[key]
+	return deadline, ok
+}
+
+// LExpireSweep removes all the keys which expired at now (unix time in nanoseconds), and returns the removed keys.
+// Keys without ttl are never removed.
+func (lis *List) LExpireSweep(now int64) []string {
+	lis.mu.Lock()
+	defer lis.mu.Unlock()
+
+	var keys []string
+	for key, deadline := range lis.expires {
+		if deadline <= now {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		lis.removeKey(key)
+	}
+	return keys
+}
+
+// LKeyExists check if the key of a List exists.
+func (lis *List) LKeyExists(key string) (ok bool) {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	_, ok = lis.record[key]
+	return
+}
+
+// LKeys returns a snapshot of all the keys of List.
+// The order of the keys is unspecified, an empty slice is returned if there are no lists.
+func (lis *List) LKeys() []string {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	keys := make([]string, 0, len(lis.record))
+	for key := range lis.record {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// LKeyCount returns the number of keys of List.
+func (lis *List) LKeyCount() int {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	return len(lis.record)
+}
+
+// LTotalElements returns the total number of elements of all the lists.
+func (lis *List) LTotalElements() int {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	total := 0
+	for _, l := range lis.record {
+		if l != nil {
+			total += l.Len()
+		}
+	}
+	return total
+}
+
+// LStats returns the statistics of all the lists, which is zero valued if there are no lists.
+func (lis *List) LStats() ListStats {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	var stats ListStats
+	stats.Keys = len(lis.record)
+	for key, l := range lis.record {
+		length := 0
+		if l != nil {
+			length = l.Len()
+		}
+
+		stats.TotalElements += length
+		if length > stats.MaxLen || stats.MaxLenKey == "" {
+			stats.MaxLen, stats.MaxLenKey = length, key
+		}
+
+		bucket := len(lenBucketBounds)
+		for i, bound := range lenBucketBounds {
+			if length <= bound {
+				bucket = i
+				break
+			}
+		}
+		stats.LenHistogram[bucket]++
+	}
+	return stats
+}
+
+// LContains check if the list stored at key contains an element equal to val.
+func (lis *List) LContains(key string, val []byte) bool {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	return lis.find(key, val) != nil
+}
+
+// LCount returns the number of elements equal to val in the list stored at key.
+func (lis *List) LCount(key string, val []byte) int {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	item := lis.record[key]
+	if item == nil {
+		return 0
+	}
+
+	count := 0
+	for p := item.Front(); p != nil; p = p.Next() {
+		if reflect.DeepEqual(valueOf(p), val) {
+			count++
+		}
+	}
+	return count
+}
+
+// LFoldLeft folds the elements of the list stored at key from head to tail, fn is called with the accumulator and every element,
+// and its result becomes the new accumulator. The final accumulator is returned, and init is returned if key does not exist.
+// fn must not modify or retain val, it is the stored value.
+// fn is called with the read lock held, so it must not call the methods of List.
+func (lis *List) LFoldLeft(key string, init []byte, fn func(acc, val []byte) []byte) []byte {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	item := lis.record[key]
+	if item == nil || fn == nil {
+		return init
+	}
+
+	acc := init
+	for p := item.Front(); p != nil; p = p.Next() {
+		acc = fn(acc, valueOf(p))
+	}
+	return acc
+}
+
+// LHash returns a 64-bit FNV-1a hash of the elements of the list stored at key from head to tail.
+// Every element is prefixed with its length, so lists with the same elements in the same order always have the same hash.
+// 0 is returned if key does not exist, an existing empty list hashes to the FNV-1a offset basis.
+func (lis *List) LHash(key string) uint64 {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	item, ok := lis.record[key]
+	if !ok {
+		return 0
+	}
+
+	h := fnv.New64a()
+	if item != nil {
+		buf := make([]byte, binary.MaxVarintLen64)
+		for p := item.Front(); p != nil; p = p.Next() {
+			val := valueOf(p)
+			n := binary.PutUvarint(buf, uint64(len(val)))
+			h.Write(buf[:n])
+			h.Write(val)
+		}
+	}
+	return h.Sum64()
+}
+
+// LCountWhere returns the number of elements for which pred returns true in the list stored at key, 0 is returned if pred is nil.
+// pred is called with the read lock held, so it must not call the methods of List.
+func (lis *List) LCountWhere(key string, pred func(val []byte) bool) int {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	item := lis.record[key]
+	if item == nil || pred == nil {
+		return 0
+	}
+
+	count := 0
+	for p := item.Front(); p != nil; p = p.Next() {
+		if pred(valueOf(p)) {
+			count++
+		}
+	}
+	return count
+}
+
+// LBinarySearch searches val in the list stored at key, which must be sorted by less, and returns the index of the first element
+// equal to val and true if it is found, otherwise the index val would be inserted at and false. If less is nil, the bytes are compared in ascending order.
+// container/list has no random access, so the values are copied to a slice first and the search still costs O(n),
+// it only saves comparisons until List has a backing with random access.
+// less is called with the read lock held, so it must not call the methods of List.
+func (lis *List) LBinarySearch(key string, val []byte, less func(a, b []byte) bool) (int, bool) {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	item := lis.record[key]
+	if item == nil || item.Len() == 0 {
+		return 0, false
+	}
+	if less == nil {
+		less = func(a, b []byte) bool {
+			return bytes.Compare(a, b) < 0
+		}
+	}
+
+	vals := make([][]byte, 0, item.Len())
+	for p := item.Front(); p != nil; p = p.Next() {
+		vals = append(vals, valueOf(p))
+	}
+	i := sort.Search(len(vals), func(i int) bool {
+		return !less(vals[i], val)
+	})
+	return i, i < len(vals) && !less(val, vals[i])
+}
+
+// LEqual check if the lists stored at aKey and bKey have the same length and equal elements in the same order.
+// false is returned if either key does not exist, two existing empty lists are equal.
+func (lis *List) LEqual(aKey, bKey string) bool {
+	lis.mu.RLock()
+	defer lis.mu.RUnlock()
+
+	a, okA := lis.record[aKey]
+	b, okB := lis.record[bKey]
+	if !okA || !okB {
+		return false
+	}
+	if lis.length(aKey) != lis.length(bKey) {
+		return false
+	}
+	if a == nil || b == nil {
+		return true
 	}
 
-	return val
+	for p, q := a.Front(), b.Front(); p != nil && q != nil; p, q = p.Next(), q.Next() {
+		if !reflect.DeepEqual(valueOf(p), valueOf(q)) {
+			return false
+		}
+	}
+	return true
 }
 
-// LRem removes the first count occurrences of elements equal to element from the list stored at key.
-// The count argument influences the operation in the following ways:
-// count > 0: Remove elements equal to element moving from head to tail.
-// count < 0: Remove elements equal to element moving from tail to head.
-// count = 0: Remove all elements equal to element.
-func (lis *List) LRem(key string, val []byte, count int) int {
-	item := lis.record[key]
-	if item == nil {
-		return 0
+func (lis *List) dumpKey(key string, l *list.List, fn dumpFunc) (err error) {
+	if l == nil {
+		return
 	}
 
-	var ele []*list.Element
-	if count == 0 {
-		for p := item.Front(); p != nil; p = p.Next() {
-			if reflect.DeepEqual(p.Value.([]byte), val) {
-				ele = append(ele, p)
-			}
+	listKey := []byte(key)
+	for e := l.Front(); e != nil; e = e.Next() {
+		value := valueOf(e)
+		// List ListRPush
+		ent := storage.NewEntryNoExtra(listKey, value, 1, 1)
+		if err = fn(ent); err != nil {
+			return
 		}
 	}
-	if count > 0 {
-		for p := item.Front(); p != nil && len(ele) < count; p = p.Next() {
-			if reflect.DeepEqual(p.Value.([]byte), val) {
-				ele = append(ele, p)
-			}
-		}
+	return
+}
+
+func (lis *List) find(key string, val []byte) *list.Element {
+	if h, ok := lis.hints[key]; ok && h.version == lis.version[key] && reflect.DeepEqual(h.val, val) {
+		return h.e
 	}
-	if count < 0 {
-		for p := item.Back(); p != nil && len(ele) < -count; p = p.Prev() {
-			if reflect.DeepEqual(p.Value.([]byte), val) {
-				ele = append(ele, p)
+
+	item := lis.record[key]
+	var e *list.Element
+
+	if item != nil {
+		for p := item.Front(); p != nil; p = p.Next() {
+			if reflect.DeepEqual(valueOf(p), val) {
+				e = p
+				break
 			}
 		}
 	}
 
-	for _, e := range ele {
-		item.Remove(e)
-	}
-	length := len(ele)
-	ele = nil
+	return e
+}
 
-	return length
+// setHint remembers e as the first element equal to val of the current version of key, so that find can return it directly.
+// It must be called with the write lock held.
+func (lis *List) setHint(key string, val []byte, e *list.Element) {
+	lis.hints[key] = findHint{version: lis.version[key], val: copyBytes(val), e: e}
 }
 
-// LInsert inserts element in the list stored at key either before or after the reference value pivot.
-func (lis *List) LInsert(key string, option InsertOption, pivot, val []byte) int {
-	e := lis.find(key, pivot)
-	if e == nil {
-		return -1
+func (lis *List) index(key string, index int) *list.Element {
+	ok, newIndex := lis.validIndex(key, index)
+	if !ok {
+		return nil
 	}
 
+	index = newIndex
 	item := lis.record[key]
-	if option == Before {
-		item.InsertBefore(val, e)
-	}
-	if option == After {
-		item.InsertAfter(val, e)
+	var e *list.Element
+
+	if item != nil && item.Len() > 0 {
+		if index <= (item.Len() >> 1) {
+			val := item.Front()
+			for i := 0; i < index; i++ {
+				val = val.Next()
+			}
+			e = val
+		} else {
+			val := item.Back()
+			for i := item.Len() - 1; i > index; i-- {
+				val = val.Prev()
+			}
+			e = val
+		}
 	}
 
-	return item.Len()
+	return e
 }
 
-// LSet sets the list element at index to element.
-func (lis *List) LSet(key string, index int, val []byte) bool {
-	e := lis.index(key, index)
-	if e == nil {
-		return false
-	}
-
-	e.Value = val
-	return true
+func (lis *List) rangeStep(key string, start, end, step int) [][]byte {
+	return lis.rangeFunc(key, start, end, step, false, nil)
 }
 
-// LRange returns the specified elements of the list stored at key.
-// The offsets start and stop are zero-based indexes, with 0 being the first element of the list (the head of the list), 1 being the next element and so on.
-// These offsets can also be negative numbers indicating offsets starting at the end of the list.
-// For example, -1 is the last element of the list, -2 the penultimate, and so on.
-func (lis *List) LRange(key string, start, end int) [][]byte {
+// rangeFunc collects the elements between start and end with mapFn applied, from tail to head if reverse is true.
+func (lis *List) rangeFunc(key string, start, end, step int, reverse bool, mapFn func(val []byte) []byte) [][]byte {
 	var val [][]byte
+	get := valueOf
+	if mapFn != nil {
+		get = func(e *list.Element) []byte {
+			return mapFn(valueOf(e))
+		}
+	}
+
 	item := lis.record[key]
 
 	if item == nil || item.Len() <= 0 {
@@ -188,34 +2791,35 @@ func (lis *List) LRange(key string, start, end int) [][]byte {
 	}
 
 	mid := length >> 1
+	backward := false
 
 	// Traverse from left to right.
 	if end <= mid || end-mid < mid-start {
 		flag := 0
 		for p := item.Front(); p != nil && flag <= end; p, flag = p.Next(), flag+1 {
-			if flag >= start {
-				val = append(val, p.Value.([]byte))
+			if flag >= start && (flag-start)%step == 0 {
+				val = append(val, get(p))
 			}
 		}
 	} else { // Traverse from right to left.
 		flag := length - 1
 		for p := item.Back(); p != nil && flag >= start; p, flag = p.Prev(), flag-1 {
-			if flag <= end {
-				val = append(val, p.Value.([]byte))
+			if flag <= end && (flag-start)%step == 0 {
+				val = append(val, get(p))
 			}
 		}
-		if len(val) > 0 {
-			for i, j := 0, len(val)-1; i < j; i, j = i+1, j-1 {
-				val[i], val[j] = val[j], val[i]
-			}
+		backward = true
+	}
+
+	if backward != reverse {
+		for i, j := 0, len(val)-1; i < j; i, j = i+1, j-1 {
+			val[i], val[j] = val[j], val[i]
 		}
 	}
 	return val
 }
 
-// LTrim trim an existing list so that it will contain only the specified range of elements specified.
-// Both start and stop are zero-based indexes, where 0 is the first element of the list (the head), 1 the next element and so on.
-func (lis *List) LTrim(key string, start, end int) bool {
+func (lis *List) trim(key string, start, end int) bool {
 	item := lis.record[key]
 	if item == nil || item.Len() <= 0 {
 		return false
@@ -229,19 +2833,15 @@ func (lis *List) LTrim(key string, start, end int) bool {
 	}
 
 	if start > end || start >= length {
-		lis.record[key] = nil
+		lis.removeKey(key)
 		return true
 	}
 
 	startEle, endEle := lis.index(key, start), lis.index(key, end)
 	if end-start+1 < (length >> 1) {
 		newList := list.New()
-		newValuesMap := make(map[string]int)
 		for p := startEle; p != endEle.Next(); p = p.Next() {
 			newList.PushBack(p.Value)
-			if p.Value != nil {
-				newValuesMap[string(p.Value.([]byte))] += 1
-			}
 		}
 
 		item = nil
@@ -260,74 +2860,37 @@ func (lis *List) LTrim(key string, start, end int) bool {
 		}
 		ele = nil
 	}
+	lis.touch(key)
 	return true
 }
 
-// LLen returns the length of the list stored at key.
-// If key does not exist, it is interpreted as an empty list and 0 is returned.
-func (lis *List) LLen(key string) int {
-	length := 0
-	if lis.record[key] != nil {
-		length = lis.record[key].Len()
-	}
-
-	return length
-}
-
-// LClear clear a specified key for List.
-func (lis *List) LClear(key string) {
-	delete(lis.record, key)
-}
-
-// LKeyExists check if the key of a List exists.
-func (lis *List) LKeyExists(key string) (ok bool) {
-	_, ok = lis.record[key]
-	return
-}
-
-func (lis *List) find(key string, val []byte) *list.Element {
+// remRange removes the elements between start and end, and returns the removed values.
+func (lis *List) remRange(key string, start, end int) [][]byte {
 	item := lis.record[key]
-	var e *list.Element
-
-	if item != nil {
-		for p := item.Front(); p != nil; p = p.Next() {
-			if reflect.DeepEqual(p.Value.([]byte), val) {
-				e = p
-				break
-			}
-		}
+	if item == nil || item.Len() <= 0 {
+		return nil
 	}
 
-	return e
-}
-
-func (lis *List) index(key string, index int) *list.Element {
-	ok, newIndex := lis.validIndex(key, index)
-	if !ok {
+	length := item.Len()
+	start, end = lis.handleIndex(length, start, end)
+	if start > end || start >= length {
 		return nil
 	}
 
-	index = newIndex
-	item := lis.record[key]
-	var e *list.Element
-
-	if item != nil && item.Len() > 0 {
-		if index <= (item.Len() >> 1) {
-			val := item.Front()
-			for i := 0; i < index; i++ {
-				val = val.Next()
-			}
-			e = val
-		} else {
-			val := item.Back()
-			for i := item.Len() - 1; i > index; i-- {
-				val = val.Prev()
-			}
-			e = val
-		}
+	removed := make([][]byte, 0, end-start+1)
+	for p, i := lis.index(key, start), start; i <= end; i++ {
+		next := p.Next()
+		removed = append(removed, valueOf(p))
+		item.Remove(p)
+		p = next
 	}
 
-	return e
+	if item.Len() == 0 {
+		lis.removeKey(key)
+	} else {
+		lis.touch(key)
+	}
+	return removed
 }
 
 func (lis *List) push(front bool, key string, val ...[]byte) int {
@@ -342,6 +2905,7 @@ func (lis *List) push(front bool, key string, val ...[]byte) int {
 			lis.record[key].PushBack(v)
 		}
 	}
+	lis.touch(key)
 	return lis.record[key].Len()
 }
 
@@ -357,12 +2921,189 @@ func (lis *List) pop(front bool, key string) []byte {
 			e = item.Back()
 		}
 
-		val = e.Value.([]byte)
+		val = valueOf(e)
 		item.Remove(e)
+		lis.touch(key)
+	}
+	return val
+}
+
+func (lis *List) popE(front bool, key string) ([]byte, error) {
+	item, ok := lis.record[key]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	if item == nil || item.Len() <= 0 {
+		return nil, ErrEmptyList
+	}
+	return lis.pop(front, key), nil
+}
+
+func (lis *List) popCount(front bool, key string, count int) [][]byte {
+	var val [][]byte
+	item := lis.record[key]
+
+	for i := 0; i < count && item != nil && item.Len() > 0; i++ {
+		val = append(val, lis.pop(front, key))
+	}
+	return val
+}
+
+// sort the elements of the list in place by swapping the values.
+func (lis *List) sort(item *list.List, less func(a, b []byte) bool) {
+	if item == nil || item.Len() <= 1 {
+		return
+	}
+
+	vals := make([][]byte, 0, item.Len())
+	for p := item.Front(); p != nil; p = p.Next() {
+		vals = append(vals, valueOf(p))
+	}
+	sort.SliceStable(vals, func(i, j int) bool {
+		return less(vals[i], vals[j])
+	})
+
+	i := 0
+	for p := item.Front(); p != nil; p, i = p.Next(), i+1 {
+		p.Value = vals[i]
+	}
+}
+
+// tooLarge check if any of the values exceeds the max value size.
+func (lis *List) tooLarge(vals ...[]byte) bool {
+	if lis.maxValueSize <= 0 {
+		return false
+	}
+	for _, v := range vals {
+		if len(v) > lis.maxValueSize {
+			return true
+		}
+	}
+	return false
+}
+
+// length returns the length of the list stored at key, 0 is returned if key does not exist.
+func (lis *List) length(key string) int {
+	if lis.record[key] == nil {
+		return 0
+	}
+	return lis.record[key].Len()
+}
+
+// setList stores l as the list of key and removes its ttl, if l is empty the key is removed.
+func (lis *List) setList(key string, l *list.List) {
+	if l.Len() == 0 {
+		lis.removeKey(key)
+		return
+	}
+	lis.record[key] = l
+	delete(lis.expires, key)
+	lis.touch(key)
+}
+
+// touch records a mutation of the list stored at key by assigning a new version to it.
+func (lis *List) touch(key string) {
+	lis.seq++
+	lis.version[key] = lis.seq
+	lis.notify(key)
+}
+
+// removeKey removes the list stored at key and all its states.
+func (lis *List) removeKey(key string) {
+	delete(lis.record, key)
+	delete(lis.version, key)
+	delete(lis.expires, key)
+	delete(lis.hints, key)
+	lis.notify(key)
+}
+
+// move moves an element from srcKey to dstKey, see LMove. It returns false if the source list is empty.
+func (lis *List) move(srcKey, dstKey string, srcFront, dstFront bool) ([]byte, bool) {
+	if lis.length(srcKey) == 0 {
+		return nil, false
+	}
+
+	val := lis.pop(srcFront, srcKey)
+	lis.push(dstFront, dstKey, val)
+	return val, true
+}
+
+// wait calls try until it succeeds and returns its result, waiting for a change of key between the attempts.
+// The key is watched before the first attempt, so changes between an attempt and the wait are not missed.
+func (lis *List) wait(ctx context.Context, key string, try func() ([]byte, bool)) ([]byte, error) {
+	ch, cancel := lis.LWatch(key)
+	defer cancel()
+
+	for {
+		if val, ok := try(); ok {
+			return val, nil
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// notify signals the watchers of key without blocking, a signal is dropped if the previous one is not received yet.
+func (lis *List) notify(key string) {
+	for ch := range lis.watchers[key] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// notifyAll signals the watchers of all the keys.
+func (lis *List) notifyAll() {
+	for key := range lis.watchers {
+		lis.notify(key)
+	}
+}
+
+// bytesTotal returns the total length of all the values of the list.
+func bytesTotal(item *list.List) int {
+	if item == nil {
+		return 0
 	}
+
+	size := 0
+	for p := item.Front(); p != nil; p = p.Next() {
+		size += len(valueOf(p))
+	}
+	return size
+}
+
+// valueOf returns the value of the element, nil is returned if the value is not a []byte.
+func valueOf(e *list.Element) []byte {
+	val, _ := e.Value.([]byte)
 	return val
 }
 
+// copy the value, so that it doesn't share the underlying array with the original one.
+func copyBytes(val []byte) []byte {
+	if val == nil {
+		return nil
+	}
+	newVal := make([]byte, len(val))
+	copy(newVal, val)
+	return newVal
+}
+
+func (lis *List) peek(front bool, key string) []byte {
+	item := lis.record[key]
+	if item == nil || item.Len() <= 0 {
+		return nil
+	}
+
+	if front {
+		return valueOf(item.Front())
+	}
+	return valueOf(item.Back())
+}
+
 // check if the index is valid and returns the new index.
 func (lis *List) validIndex(key string, index int) (bool, int) {
 	item := lis.record[key]
@@ -1,8 +1,17 @@
 package list
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"github.com/roseduan/rosedb/storage"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"runtime"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 )
 
 var key = "my_list"
@@ -16,6 +25,15 @@ func InitList() *List {
 	return list
 }
 
+// InitListWith creates a List holding vals from head to tail under k.
+func InitListWith(k string, vals ...string) *List {
+	list := New()
+	for _, v := range vals {
+		list.RPush(k, []byte(v))
+	}
+	return list
+}
+
 func PrintListData(lis *List) {
 	if lis.record[key] == nil || lis.record[key].Len() <= 0 {
 		fmt.Println("list is empty")
@@ -29,6 +47,139 @@ func PrintListData(lis *List) {
 	fmt.Println()
 }
 
+func TestList_DumpIterate(t *testing.T) {
+	list := InitList()
+	list.RPush(key, []byte("g"), []byte("h"))
+	list.LPush(key, []byte("i"))
+	list.RPush("another_list", []byte("x"), []byte("y"))
+	list.LPush("another_list", []byte("z"))
+
+	replayed := New()
+	err := list.DumpIterate(func(e *storage.Entry) error {
+		k := string(e.Meta.Key)
+		// 0 is ListLPush and 1 is ListRPush.
+		if e.GetMark() == 0 {
+			replayed.LPush(k, e.Meta.Value)
+		} else {
+			replayed.RPush(k, e.Meta.Value)
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+
+	for _, k := range []string{key, "another_list"} {
+		assert.Equal(t, list.LRange(k, 0, -1), replayed.LRange(k, 0, -1))
+	}
+}
+
+func TestList_DumpIterateBatched(t *testing.T) {
+	list := InitList()
+	list.RPush("k1", []byte("x"), []byte("y"))
+
+	dumped := make(map[string][][]byte)
+	err := list.DumpIterateBatched(func(key []byte, vals [][]byte) error {
+		dumped[string(key)] = vals
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(dumped))
+	assert.Equal(t, list.LRange(key, 0, -1), dumped[key])
+	assert.Equal(t, [][]byte{[]byte("x"), []byte("y")}, dumped["k1"])
+
+	dumpErr := fmt.Errorf("dump error")
+	err = list.DumpIterateBatched(func(key []byte, vals [][]byte) error {
+		return dumpErr
+	})
+	assert.Equal(t, dumpErr, err)
+}
+
+func TestList_Marshal(t *testing.T) {
+	list := InitList()
+	list.RPush("列表", []byte("值"), []byte(""), []byte("value"))
+	list.RPush("empty_list", []byte("a"))
+	list.LPop("empty_list")
+
+	data, err := list.Marshal()
+	assert.Nil(t, err)
+
+	newList := New()
+	newList.RPush("old_list", []byte("a"))
+	err = newList.Unmarshal(data)
+	assert.Nil(t, err)
+
+	assert.ElementsMatch(t, list.LKeys(), newList.LKeys())
+	for _, k := range list.LKeys() {
+		assert.Equal(t, list.LLen(k), newList.LLen(k))
+		assert.Equal(t, list.LRange(k, 0, -1), newList.LRange(k, 0, -1))
+	}
+	assert.True(t, newList.LKeyExists("empty_list"))
+	assert.False(t, newList.LKeyExists("old_list"))
+
+	err = newList.Unmarshal(data[:len(data)-1])
+	assert.Equal(t, ErrInvalidData, err)
+	assert.Equal(t, 3, len(newList.LKeys()))
+
+	// huge counts that the data can not hold are rejected without allocating for them.
+	err = newList.Unmarshal([]byte{0xff, 0xff, 0xff, 0xff})
+	assert.Equal(t, ErrInvalidData, err)
+	err = newList.Unmarshal([]byte{0, 0, 0, 1, 0, 0, 0, 1, 'k', 0xff, 0xff, 0xff, 0xff})
+	assert.Equal(t, ErrInvalidData, err)
+	assert.Equal(t, 3, len(newList.LKeys()))
+}
+
+func TestList_Snapshot(t *testing.T) {
+	list := InitList()
+	list.RPush("other", []byte("x"), []byte("y"))
+	list.RPush("empty", []byte("z"))
+	list.LPop("empty")
+
+	snapshot := list.Snapshot()
+	assert.Equal(t, 3, len(snapshot))
+	assert.Equal(t, 0, len(snapshot["empty"]))
+
+	snapshot[key][0][0] = 'z'
+	assert.Equal(t, []byte("f"), list.LIndex(key, 0))
+	snapshot[key][0][0] = 'f'
+
+	restored := New()
+	restored.RPush("stale", []byte("1"))
+	assert.True(t, restored.Restore(snapshot))
+	assert.False(t, restored.LKeyExists("stale"))
+	assert.True(t, restored.LKeyExists("empty"))
+
+	// copy the original lists next to the restored ones to compare them.
+	for k := range snapshot {
+		restored.RPush("copy_"+k, list.LRange(k, 0, -1)...)
+		assert.True(t, restored.LEqual(k, "copy_"+k))
+	}
+
+	snapshot[key][1][0] = 'z'
+	assert.Equal(t, []byte("e"), restored.LIndex(key, 1))
+}
+
+func TestList_DumpIterateKeys(t *testing.T) {
+	list := InitList()
+	list.RPush("k1", []byte("x"), []byte("y"))
+	list.RPush("k2", []byte("z"))
+
+	dumped := make(map[string][][]byte)
+	fn := func(e *storage.Entry) error {
+		k := string(e.Meta.Key)
+		dumped[k] = append(dumped[k], e.Meta.Value)
+		return nil
+	}
+
+	err := list.DumpIterateKeys(fn, "k1", "not")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][][]byte{"k1": {[]byte("x"), []byte("y")}}, dumped)
+
+	dumped = make(map[string][][]byte)
+	err = list.DumpIterateKeys(fn)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(dumped))
+	assert.Equal(t, list.LRange(key, 0, -1), dumped[key])
+}
+
 func TestList_LPush(t *testing.T) {
 	list := InitList()
 
@@ -45,6 +196,100 @@ func TestList_RPush(t *testing.T) {
 	t.Log("size = ", size)
 }
 
+func TestList_LPushBatch(t *testing.T) {
+	vals := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+	l1, l2 := New(), New()
+
+	assert.Equal(t, 3, l1.LPushBatch(key, vals))
+	l2.LPush(key, vals...)
+	assert.Equal(t, l2.LRange(key, 0, -1), l1.LRange(key, 0, -1))
+	assert.Equal(t, []byte("c"), l1.LIndex(key, 0))
+}
+
+func TestList_RPushBatch(t *testing.T) {
+	vals := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+	list := InitList()
+
+	assert.Equal(t, 9, list.RPushBatch(key, vals))
+	assert.Equal(t, vals, list.LRange(key, -3, -1))
+}
+
+func TestList_LEnsureCapacity(t *testing.T) {
+	list := InitList()
+
+	list.LEnsureCapacity("new", 1000)
+	assert.True(t, list.LKeyExists("new"))
+	assert.Equal(t, 0, list.LLen("new"))
+	assert.Equal(t, 1, list.RPush("new", []byte("a")))
+
+	list.LEnsureCapacity(key, 1000)
+	assert.Equal(t, 6, list.LLen(key))
+}
+
+func TestList_LPopUntil(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("1"), []byte("2"), []byte("5"), []byte("3"), []byte("4"))
+	ready := func(val []byte) bool { return string(val) < "5" }
+
+	assert.Equal(t, [][]byte{[]byte("1"), []byte("2")}, list.LPopUntil(key, true, ready))
+	assert.Equal(t, [][]byte{[]byte("4"), []byte("3")}, list.LPopUntil(key, false, ready))
+	assert.Empty(t, list.LPopUntil(key, true, ready))
+	assert.Equal(t, [][]byte{[]byte("5")}, list.LRange(key, 0, -1))
+
+	all := func(val []byte) bool { return true }
+	assert.Equal(t, 1, len(list.LPopUntil(key, true, all)))
+	assert.NotNil(t, list.LPopUntil(key, true, all))
+	assert.Empty(t, list.LPopUntil(key, true, all))
+	assert.NotNil(t, list.LPopUntil("not", true, all))
+	assert.Empty(t, list.LPopUntil("not", true, all))
+}
+
+func TestList_LPushX(t *testing.T) {
+	list := InitList()
+
+	size := list.LPushX(key, []byte("g"), []byte("h"))
+	assert.Equal(t, 8, size)
+	assert.Equal(t, []byte("h"), list.LIndex(key, 0))
+
+	size = list.LPushX("not_exist", []byte("a"), []byte("b"))
+	assert.Equal(t, 0, size)
+	assert.False(t, list.LKeyExists("not_exist"))
+}
+
+func TestList_RPushX(t *testing.T) {
+	list := InitList()
+
+	size := list.RPushX(key, []byte("g"), []byte("h"))
+	assert.Equal(t, 8, size)
+	assert.Equal(t, []byte("h"), list.LIndex(key, -1))
+
+	size = list.RPushX("not_exist", []byte("a"), []byte("b"))
+	assert.Equal(t, 0, size)
+	assert.False(t, list.LKeyExists("not_exist"))
+}
+
+func TestList_LPushUnique(t *testing.T) {
+	list := InitList()
+
+	assert.Equal(t, 6, list.LPushUnique(key, []byte("c")))
+	assert.Equal(t, 7, list.LPushUnique(key, []byte("g")))
+	assert.Equal(t, 7, list.LPushUnique(key, []byte("g")))
+	assert.Equal(t, []byte("g"), list.LIndex(key, 0))
+
+	assert.Equal(t, 1, list.LPushUnique("new_list", []byte("a")))
+}
+
+func TestList_LPushIfChanged(t *testing.T) {
+	list := New()
+
+	assert.Equal(t, 1, list.LPushIfChanged(key, []byte("a")))
+	assert.Equal(t, 1, list.LPushIfChanged(key, []byte("a")))
+	assert.Equal(t, 1, list.LPushIfChanged(key, []byte("a")))
+	assert.Equal(t, 2, list.LPushIfChanged(key, []byte("b")))
+	assert.Equal(t, 3, list.LPushIfChanged(key, []byte("a")))
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("a")}, list.LRange(key, 0, -1))
+}
+
 func TestList_LPop(t *testing.T) {
 	list := InitList()
 
@@ -62,6 +307,161 @@ func TestList_LPop(t *testing.T) {
 	t.Log(list.record[key].Len())
 }
 
+func TestList_LPopCount(t *testing.T) {
+	list := InitList()
+
+	//f e d c b a
+	vals := list.LPopCount(key, 2)
+	assert.Equal(t, [][]byte{[]byte("f"), []byte("e")}, vals)
+	assert.Equal(t, 4, list.LLen(key))
+
+	vals = list.LPopCount(key, 10)
+	assert.Equal(t, 4, len(vals))
+	assert.Equal(t, 0, list.LLen(key))
+	assert.True(t, list.LKeyExists(key))
+
+	assert.Empty(t, list.LPopCount("not_exist", 1))
+}
+
+func TestList_LPopN(t *testing.T) {
+	list := InitList()
+
+	//f e d c b a
+	assert.Equal(t, [][]byte{[]byte("f"), []byte("e")}, list.LPopN(key, 2))
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, list.LPopN(key, -2))
+	assert.NotNil(t, list.LPopN(key, 0))
+	assert.Empty(t, list.LPopN(key, 0))
+	assert.Equal(t, 2, list.LLen(key))
+
+	assert.Equal(t, 2, len(list.LPopN(key, -5)))
+	assert.NotNil(t, list.LPopN("not", 1))
+	assert.Empty(t, list.LPopN("not", 1))
+}
+
+func TestList_LPopHeader(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("hdr:payload"), []byte("ab"))
+
+	header, body := list.LPopHeader(key, 4)
+	assert.Equal(t, []byte("hdr:"), header)
+	assert.Equal(t, []byte("payload"), body)
+
+	header, body = list.LPopHeader(key, 4)
+	assert.Equal(t, []byte("ab"), header)
+	assert.Equal(t, []byte{}, body)
+
+	header, body = list.LPopHeader(key, 4)
+	assert.Nil(t, header)
+	assert.Nil(t, body)
+	header, body = list.LPopHeader("not", 4)
+	assert.Nil(t, header)
+	assert.Nil(t, body)
+}
+
+func TestList_RPopCount(t *testing.T) {
+	list := InitList()
+
+	//f e d c b a
+	vals := list.RPopCount(key, 2)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, vals)
+	assert.Equal(t, 4, list.LLen(key))
+
+	vals = list.RPopCount(key, 10)
+	assert.Equal(t, 4, len(vals))
+	assert.Equal(t, 0, list.LLen(key))
+
+	assert.Empty(t, list.RPopCount(key, 0))
+}
+
+func TestList_LPopE(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("a"))
+
+	val, err := list.LPopE(key)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("a"), val)
+
+	_, err = list.LPopE(key)
+	assert.Equal(t, ErrEmptyList, err)
+	_, err = list.LPopE("not")
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func TestList_RPopE(t *testing.T) {
+	list := InitList()
+
+	val, err := list.RPopE(key)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("a"), val)
+
+	list.RPopCount(key, 5)
+	_, err = list.RPopE(key)
+	assert.Equal(t, ErrEmptyList, err)
+	_, err = list.RPopE("not")
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func TestList_BLPop(t *testing.T) {
+	list := InitList()
+
+	val, err := list.BLPop(context.Background(), key)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("f"), val)
+	val, err = list.BRPop(context.Background(), key)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("a"), val)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	val, err = list.BLPop(ctx, "empty")
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Nil(t, val)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		list.RPush("queue", []byte("x"), []byte("y"))
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	val, err = list.BRPop(ctx, "queue")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("y"), val)
+	val, err = list.BLPop(ctx, "queue")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("x"), val)
+}
+
+func TestList_BLPopConcurrent(t *testing.T) {
+	list := New()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 250; j++ {
+				val, err := list.BLPop(ctx, key)
+				if !assert.Nil(t, err) {
+					return
+				}
+				mu.Lock()
+				seen[string(val)]++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := 0; i < 1000; i++ {
+		list.RPush(key, []byte(strconv.Itoa(i)))
+	}
+	wg.Wait()
+	assert.Equal(t, 1000, len(seen))
+}
+
 func TestList_RPop(t *testing.T) {
 	list := InitList()
 
@@ -77,6 +477,219 @@ func TestList_RPop(t *testing.T) {
 	t.Log(list.record[key].Len())
 }
 
+func TestList_LDrain(t *testing.T) {
+	list := InitList()
+
+	vals := list.LDrain(key)
+	assert.Equal(t, 6, len(vals))
+	assert.Equal(t, []byte("f"), vals[0])
+	assert.Equal(t, []byte("a"), vals[5])
+	assert.False(t, list.LKeyExists(key))
+	assert.NotNil(t, list.LDrain(key))
+	assert.Empty(t, list.LDrain(key))
+}
+
+func TestList_LDrainConcurrent(t *testing.T) {
+	lis := New()
+	wg := new(sync.WaitGroup)
+	done := make(chan struct{})
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				lis.RPush(key, []byte(strconv.Itoa(i*1000+j)))
+			}
+		}(i)
+	}
+
+	seen := make(map[string]int)
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				for _, v := range lis.LDrain(key) {
+					seen[string(v)]++
+				}
+			}
+		}
+	}()
+	wg.Wait()
+	close(done)
+	<-drained
+
+	for _, v := range lis.LDrain(key) {
+		seen[string(v)]++
+	}
+	assert.Equal(t, 4000, len(seen))
+	for _, n := range seen {
+		assert.Equal(t, 1, n)
+	}
+}
+
+func TestList_LMove(t *testing.T) {
+	t.Run("different keys", func(t *testing.T) {
+		list := InitList()
+		dstKey := "dst_list"
+
+		//f e d c b a
+		val := list.LMove(key, dstKey, false, true)
+		assert.Equal(t, []byte("a"), val)
+		val = list.LMove(key, dstKey, true, false)
+		assert.Equal(t, []byte("f"), val)
+
+		assert.Equal(t, 4, list.LLen(key))
+		assert.Equal(t, [][]byte{[]byte("a"), []byte("f")}, list.LRange(dstKey, 0, -1))
+	})
+
+	t.Run("same key", func(t *testing.T) {
+		list := InitList()
+
+		val := list.LMove(key, key, false, true)
+		assert.Equal(t, []byte("a"), val)
+		assert.Equal(t, 6, list.LLen(key))
+		assert.Equal(t, []byte("a"), list.LIndex(key, 0))
+	})
+
+	t.Run("empty source", func(t *testing.T) {
+		list := New()
+		val := list.LMove("src_list", "dst_list", true, true)
+		assert.Nil(t, val)
+		assert.False(t, list.LKeyExists("dst_list"))
+	})
+}
+
+func TestList_LMoveN(t *testing.T) {
+	t.Run("preserve order", func(t *testing.T) {
+		list := InitListWith("src", "a", "b", "c")
+		assert.Equal(t, 2, list.LMoveN("src", "dst", true, false, 2))
+		assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, list.LRange("dst", 0, -1))
+		assert.Equal(t, [][]byte{[]byte("c")}, list.LRange("src", 0, -1))
+	})
+
+	t.Run("reverse order", func(t *testing.T) {
+		list := InitListWith("src", "a", "b", "c")
+		assert.Equal(t, 3, list.LMoveN("src", "dst", true, true, 5))
+		assert.Equal(t, [][]byte{[]byte("c"), []byte("b"), []byte("a")}, list.LRange("dst", 0, -1))
+		assert.Equal(t, 0, list.LLen("src"))
+	})
+
+	t.Run("empty source", func(t *testing.T) {
+		list := New()
+		assert.Equal(t, 0, list.LMoveN("src", "dst", true, true, 5))
+		assert.False(t, list.LKeyExists("dst"))
+	})
+
+	t.Run("same key", func(t *testing.T) {
+		list := InitListWith("src", "a", "b", "c")
+		assert.Equal(t, 3, list.LMoveN("src", "src", true, false, math.MaxInt64))
+		assert.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("c")}, list.LRange("src", 0, -1))
+		assert.Equal(t, 2, list.LMoveN("src", "src", true, false, 2))
+		assert.Equal(t, [][]byte{[]byte("c"), []byte("a"), []byte("b")}, list.LRange("src", 0, -1))
+	})
+}
+
+func TestList_LMoveByValue(t *testing.T) {
+	list := InitList()
+
+	assert.True(t, list.LMoveByValue(key, []byte("c"), true))
+	assert.Equal(t, []byte("c"), list.LIndex(key, 0))
+	assert.True(t, list.LMoveByValue(key, []byte("e"), false))
+	assert.Equal(t, []byte("e"), list.LIndex(key, -1))
+	assert.True(t, list.LMoveByValue(key, []byte("e"), false))
+
+	expected := [][]byte{[]byte("c"), []byte("f"), []byte("d"), []byte("b"), []byte("a"), []byte("e")}
+	assert.Equal(t, expected, list.LRange(key, 0, -1))
+
+	assert.False(t, list.LMoveByValue(key, []byte("x"), true))
+	assert.False(t, list.LMoveByValue("not", []byte("a"), true))
+}
+
+func TestList_RPopLPush(t *testing.T) {
+	t.Run("rotation", func(t *testing.T) {
+		list := New()
+		list.RPush(key, []byte("a"), []byte("b"), []byte("c"))
+
+		assert.Equal(t, []byte("c"), list.RPopLPush(key, key))
+		assert.Equal(t, [][]byte{[]byte("c"), []byte("a"), []byte("b")}, list.LRange(key, 0, -1))
+
+		assert.Equal(t, []byte("b"), list.RPopLPush(key, key))
+		assert.Equal(t, []byte("a"), list.RPopLPush(key, key))
+		assert.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("c")}, list.LRange(key, 0, -1))
+	})
+
+	t.Run("empty source", func(t *testing.T) {
+		list := New()
+		assert.Nil(t, list.RPopLPush("src_list", "dst_list"))
+		assert.False(t, list.LKeyExists("dst_list"))
+	})
+}
+
+func TestList_BLMove(t *testing.T) {
+	list := InitList()
+
+	val, err := list.BLMove(context.Background(), key, key, true, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("f"), val)
+	assert.Equal(t, []byte("f"), list.LIndex(key, -1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	val, err = list.BLMove(ctx, "queue", "processing", true, false)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Nil(t, val)
+	assert.False(t, list.LKeyExists("processing"))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		list.RPush("queue", []byte("job"))
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	val, err = list.BLMove(ctx, "queue", "processing", true, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("job"), val)
+	assert.Equal(t, 0, list.LLen("queue"))
+	assert.Equal(t, [][]byte{[]byte("job")}, list.LRange("processing", 0, -1))
+}
+
+func TestList_LMPop(t *testing.T) {
+	list := New()
+	list.RPush("queue2", []byte("a"), []byte("b"), []byte("c"))
+	list.RPush("queue3", []byte("d"))
+	list.record["queue1"] = nil
+
+	k, vals := list.LMPop(true, 2, "queue0", "queue1", "queue2", "queue3")
+	assert.Equal(t, "queue2", k)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, vals)
+
+	k, vals = list.LMPop(false, 2, "queue2", "queue3")
+	assert.Equal(t, "queue2", k)
+	assert.Equal(t, [][]byte{[]byte("c")}, vals)
+
+	k, vals = list.LMPop(false, 2, "queue2", "queue3")
+	assert.Equal(t, "queue3", k)
+	assert.Equal(t, [][]byte{[]byte("d")}, vals)
+
+	k, vals = list.LMPop(true, 1, "queue1", "queue2", "queue3")
+	assert.Equal(t, "", k)
+	assert.Nil(t, vals)
+
+	list.RPush("queue4", []byte("e"))
+	k, vals = list.LMPop(true, 0, "queue4")
+	assert.Equal(t, "", k)
+	assert.Nil(t, vals)
+	k, vals = list.LMPop(true, -1, "queue4")
+	assert.Equal(t, "", k)
+	assert.Nil(t, vals)
+	assert.Equal(t, 1, list.LLen("queue4"))
+}
+
 func TestList_LIndex(t *testing.T) {
 	list := InitList()
 
@@ -94,6 +707,103 @@ func TestList_LIndex(t *testing.T) {
 	t.Log(string(list.LIndex(key, -100)))
 }
 
+func TestList_LIndexCopy(t *testing.T) {
+	list := InitList()
+
+	val := list.LIndexCopy(key, 0)
+	assert.Equal(t, []byte("f"), val)
+	val[0] = 'x'
+	_ = append(val[:0], 'y')
+	assert.Equal(t, []byte("f"), list.LIndex(key, 0))
+
+	assert.Nil(t, list.LIndexCopy(key, 10))
+	assert.Nil(t, list.LIndexCopy("not", 0))
+}
+
+func TestList_LIndexMulti(t *testing.T) {
+	list := InitList()
+
+	vals := list.LIndexMulti(key, 4, 0, -1, 10, 4, -7)
+	assert.Equal(t, [][]byte{[]byte("b"), []byte("f"), []byte("a"), nil, []byte("b"), nil}, vals)
+	assert.Equal(t, [][]byte{nil, nil}, list.LIndexMulti("not", 0, 1))
+	assert.Empty(t, list.LIndexMulti(key))
+}
+
+func TestList_LPeek(t *testing.T) {
+	list := InitList()
+
+	assert.Equal(t, []byte("f"), list.LPeek(key))
+	assert.Equal(t, []byte("f"), list.LPeek(key))
+	assert.Equal(t, 6, list.LLen(key))
+	assert.Nil(t, list.LPeek("not"))
+}
+
+func TestList_RPeek(t *testing.T) {
+	list := InitList()
+
+	assert.Equal(t, []byte("a"), list.RPeek(key))
+	assert.Equal(t, []byte("a"), list.RPeek(key))
+	assert.Equal(t, 6, list.LLen(key))
+	assert.Nil(t, list.RPeek("not"))
+}
+
+func TestList_LPos(t *testing.T) {
+	list := InitList()
+	list.RPush(key, []byte("f"))
+
+	//f e d c b a f
+	assert.Equal(t, 0, list.LPos(key, []byte("f")))
+	assert.Equal(t, 5, list.LPos(key, []byte("a")))
+	assert.Equal(t, -1, list.LPos(key, []byte("x")))
+	assert.Equal(t, -1, list.LPos("not_exist", []byte("a")))
+}
+
+func TestList_LPosRank(t *testing.T) {
+	list := InitList()
+	list.RPush(key, []byte("f"))
+
+	//f e d c b a f
+	assert.Equal(t, 0, list.LPosRank(key, []byte("f"), 1))
+	assert.Equal(t, 6, list.LPosRank(key, []byte("f"), 2))
+	assert.Equal(t, -1, list.LPosRank(key, []byte("f"), 3))
+	assert.Equal(t, 6, list.LPosRank(key, []byte("f"), -1))
+	assert.Equal(t, 0, list.LPosRank(key, []byte("f"), -2))
+	assert.Equal(t, -1, list.LPosRank(key, []byte("f"), 0))
+}
+
+func TestList_LPosCount(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("a"), []byte("b"), []byte("a"), []byte("c"), []byte("a"))
+
+	assert.Equal(t, []int{0, 2, 4}, list.LPosCount(key, []byte("a"), 0))
+	assert.Equal(t, []int{0, 2}, list.LPosCount(key, []byte("a"), 2))
+	assert.Equal(t, []int{4, 2}, list.LPosCount(key, []byte("a"), -2))
+	assert.Equal(t, []int{4, 2, 0}, list.LPosCount(key, []byte("a"), -10))
+	assert.Empty(t, list.LPosCount(key, []byte("x"), 0))
+	assert.Empty(t, list.LPosCount("not_exist", []byte("a"), 0))
+}
+
+func TestList_LFindAll(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("a"), []byte("b"), []byte("a"), []byte("c"))
+
+	indexes, values := list.LFindAll(key, []byte("a"))
+	assert.Equal(t, []int{0, 2}, indexes)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("a")}, values)
+
+	indexes, values = list.LFindAll(key, []byte("x"))
+	assert.NotNil(t, indexes)
+	assert.NotNil(t, values)
+	assert.Empty(t, indexes)
+	assert.Empty(t, values)
+
+	indexes, values = list.LFindAll("not", []byte("a"))
+	assert.NotNil(t, indexes)
+	assert.NotNil(t, values)
+	assert.Empty(t, indexes)
+	assert.Empty(t, values)
+}
+
 func TestList_LRem(t *testing.T) {
 	list := InitList()
 
@@ -135,6 +845,195 @@ func TestList_LRem(t *testing.T) {
 	PrintListData(lis)
 }
 
+func TestList_LRemFirst(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("a"), []byte("b"), []byte("a"), []byte("c"), []byte("a"))
+
+	assert.True(t, list.LRemFirst(key, []byte("a")))
+	assert.Equal(t, [][]byte{[]byte("b"), []byte("a"), []byte("c"), []byte("a")}, list.LRange(key, 0, -1))
+
+	assert.True(t, list.LRemLast(key, []byte("a")))
+	assert.Equal(t, [][]byte{[]byte("b"), []byte("a"), []byte("c")}, list.LRange(key, 0, -1))
+
+	assert.False(t, list.LRemFirst(key, []byte("x")))
+	assert.False(t, list.LRemLast(key, []byte("x")))
+	assert.False(t, list.LRemFirst("not", []byte("a")))
+	assert.Equal(t, 3, list.LLen(key))
+}
+
+func TestList_LRemWhere(t *testing.T) {
+	long := func(val []byte) bool { return len(val) > 1 }
+
+	list := InitListWith(key, "a", "bbb", "cc", "dddd", "e")
+	assert.Equal(t, 3, list.LRemWhere(key, long, 0))
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("e")}, list.LRange(key, 0, -1))
+
+	list = InitListWith(key, "a", "bbb", "cc", "dddd", "e")
+	assert.Equal(t, 2, list.LRemWhere(key, long, 2))
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("dddd"), []byte("e")}, list.LRange(key, 0, -1))
+
+	list = InitListWith(key, "a", "bbb", "cc", "dddd", "e")
+	assert.Equal(t, 1, list.LRemWhere(key, long, -1))
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("bbb"), []byte("cc"), []byte("e")}, list.LRange(key, 0, -1))
+
+	assert.Equal(t, 0, list.LRemWhere(key, nil, 0))
+	assert.Equal(t, 0, list.LRemWhere("not", long, 0))
+}
+
+func TestList_LReplace(t *testing.T) {
+	list := InitListWith(key, "a", "b", "a", "a")
+	assert.Equal(t, 3, list.LReplace(key, []byte("a"), []byte("x"), 0))
+	assert.Equal(t, [][]byte{[]byte("x"), []byte("b"), []byte("x"), []byte("x")}, list.LRange(key, 0, -1))
+
+	list = InitListWith(key, "a", "b", "a", "a")
+	assert.Equal(t, 2, list.LReplace(key, []byte("a"), []byte("x"), 2))
+	assert.Equal(t, [][]byte{[]byte("x"), []byte("b"), []byte("x"), []byte("a")}, list.LRange(key, 0, -1))
+
+	list = InitListWith(key, "a", "b", "a", "a")
+	assert.Equal(t, 1, list.LReplace(key, []byte("a"), []byte("x"), -1))
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("a"), []byte("x")}, list.LRange(key, 0, -1))
+
+	list = InitListWith(key, "a", "b", "a", "a")
+	v := list.LVersion(key)
+	assert.Equal(t, 0, list.LReplace(key, []byte("a"), []byte("a"), 0))
+	assert.Equal(t, v, list.LVersion(key))
+	assert.Equal(t, 0, list.LReplace("not", []byte("a"), []byte("x"), 0))
+}
+
+func TestList_LRemValues(t *testing.T) {
+	t.Run("all", func(t *testing.T) {
+		list := InitListWith(key, "a", "b", "c", "a", "b", "a")
+		assert.Equal(t, 5, list.LRemValues(key, 0, []byte("a"), []byte("b"), []byte("x")))
+		assert.Equal(t, [][]byte{[]byte("c")}, list.LRange(key, 0, -1))
+	})
+
+	t.Run("head", func(t *testing.T) {
+		list := InitListWith(key, "a", "b", "c", "a", "b", "a")
+		assert.Equal(t, 2, list.LRemValues(key, 1, []byte("a"), []byte("b")))
+		assert.Equal(t, [][]byte{[]byte("c"), []byte("a"), []byte("b"), []byte("a")}, list.LRange(key, 0, -1))
+	})
+
+	t.Run("tail", func(t *testing.T) {
+		list := InitListWith(key, "a", "b", "c", "a", "b", "a")
+		assert.Equal(t, 3, list.LRemValues(key, -2, []byte("a"), []byte("c")))
+		assert.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("b")}, list.LRange(key, 0, -1))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		list := New()
+		assert.Equal(t, 0, list.LRemValues(key, 0, []byte("a")))
+	})
+}
+
+func TestList_LDedup(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("b"), []byte("a"), []byte("b"), []byte("c"), []byte("a"))
+
+	assert.Equal(t, 2, list.LDedup(key))
+	assert.Equal(t, [][]byte{[]byte("b"), []byte("a"), []byte("c")}, list.LRange(key, 0, -1))
+	assert.Equal(t, 0, list.LDedup(key))
+
+	list.RPush("same", []byte("x"), []byte("x"), []byte("x"))
+	assert.Equal(t, 2, list.LDedup("same"))
+	assert.Equal(t, [][]byte{[]byte("x")}, list.LRange("same", 0, -1))
+
+	assert.Equal(t, 0, list.LDedup("not"))
+}
+
+func TestList_LRemAt(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("a"), []byte("b"), []byte("c"))
+
+	assert.Equal(t, []byte("b"), list.LRemAt(key, 1))
+	assert.Equal(t, []byte("c"), list.LRemAt(key, -1))
+	assert.Nil(t, list.LRemAt(key, 1))
+	assert.Equal(t, [][]byte{[]byte("a")}, list.LRange(key, 0, -1))
+
+	assert.Equal(t, []byte("a"), list.LRemAt(key, 0))
+	assert.False(t, list.LKeyExists(key))
+	assert.Nil(t, list.LRemAt("not", 0))
+}
+
+func TestList_LRemRange(t *testing.T) {
+	list := InitList()
+
+	//f e d c b a
+	assert.Equal(t, 2, list.LRemRange(key, 1, 2))
+	assert.Equal(t, [][]byte{[]byte("f"), []byte("c"), []byte("b"), []byte("a")}, list.LRange(key, 0, -1))
+
+	assert.Equal(t, 2, list.LRemRange(key, -2, 100))
+	assert.Equal(t, [][]byte{[]byte("f"), []byte("c")}, list.LRange(key, 0, -1))
+
+	assert.Equal(t, 0, list.LRemRange(key, 1, 0))
+	assert.Equal(t, 0, list.LRemRange(key, 5, 10))
+
+	assert.Equal(t, 2, list.LRemRange(key, 0, -1))
+	assert.False(t, list.LKeyExists(key))
+	assert.Equal(t, 0, list.LRemRange("not", 0, -1))
+}
+
+func TestList_LSplice(t *testing.T) {
+	list := InitList()
+
+	//f e d c b a
+	removed := list.LSplice(key, 1, 2, []byte("x"), []byte("y"), []byte("z"))
+	assert.Equal(t, [][]byte{[]byte("e"), []byte("d")}, removed)
+	expected := [][]byte{[]byte("f"), []byte("x"), []byte("y"), []byte("z"), []byte("c"), []byte("b"), []byte("a")}
+	assert.Equal(t, expected, list.LRange(key, 0, -1))
+
+	removed = list.LSplice(key, -2, 10, []byte("m"))
+	assert.Equal(t, [][]byte{[]byte("b"), []byte("a")}, removed)
+	assert.Equal(t, []byte("m"), list.LIndex(key, -1))
+	assert.Equal(t, 6, list.LLen(key))
+
+	removed = list.LSplice(key, 0, 0, []byte("n"))
+	assert.Empty(t, removed)
+	assert.Equal(t, []byte("n"), list.LIndex(key, 0))
+
+	removed = list.LSplice(key, 2, math.MaxInt64, []byte("o"))
+	assert.Equal(t, [][]byte{[]byte("x"), []byte("y"), []byte("z"), []byte("c"), []byte("m")}, removed)
+	expected = [][]byte{[]byte("n"), []byte("f"), []byte("o")}
+	assert.Equal(t, expected, list.LRange(key, 0, -1))
+
+	removed = list.LSplice(key, 0, 100)
+	assert.Equal(t, 3, len(removed))
+	assert.False(t, list.LKeyExists(key))
+
+	removed = list.LSplice("not", 0, 1, []byte("a"), []byte("b"))
+	assert.Empty(t, removed)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, list.LRange("not", 0, -1))
+}
+
+func TestList_LSpliceMove(t *testing.T) {
+	toStr := func(vals [][]byte) string {
+		return string(bytes.Join(vals, nil))
+	}
+	tests := []struct {
+		start, count, dest int
+		ok                 bool
+		expected           string
+	}{
+		{1, 2, 0, true, "edfcba"},
+		{0, 2, 4, true, "dcbafe"},
+		{0, 2, 2, true, "dcfeba"},
+		{3, 10, 1, true, "fcbaed"},
+		{-2, 2, 0, true, "bafedc"},
+		{0, 1, -1, true, "edcbfa"},
+		{2, 2, 2, true, "fedcba"},
+		{0, 2, 5, false, "fedcba"},
+		{6, 1, 0, false, "fedcba"},
+		{0, 0, 1, false, "fedcba"},
+	}
+
+	for _, tt := range tests {
+		list := InitList()
+		assert.Equal(t, tt.ok, list.LSpliceMove(key, tt.start, tt.count, tt.dest))
+		assert.Equal(t, tt.expected, toStr(list.LRange(key, 0, -1)))
+	}
+
+	assert.False(t, New().LSpliceMove("not", 0, 1, 0))
+}
+
 func TestList_LInsert(t *testing.T) {
 
 	list := InitList()
@@ -166,10 +1065,98 @@ func TestList_LInsert(t *testing.T) {
 	})
 }
 
-func TestList_LSet(t *testing.T) {
-	list := InitList()
-	ok := list.LSet(key, 0, []byte("FF"))
-	t.Log(ok)
+func TestList_FindHint(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("a"), []byte("b"), []byte("c"))
+
+	assert.Equal(t, 4, list.LInsert(key, Before, []byte("c"), []byte("x")))
+	assert.Equal(t, 5, list.LInsert(key, Before, []byte("c"), []byte("y")))
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("x"), []byte("y"), []byte("c")}, list.LRange(key, 0, -1))
+
+	// the hint is invalidated by other mutations.
+	list.LRemAt(key, -1)
+	assert.Equal(t, -1, list.LInsert(key, Before, []byte("c"), []byte("z")))
+
+	list.RPush(key, []byte("c"))
+	list.LSet(key, 0, []byte("c"))
+	assert.Equal(t, 6, list.LInsert(key, After, []byte("c"), []byte("z")))
+	assert.Equal(t, []byte("z"), list.LIndex(key, 1))
+
+	// inserting a value equal to the pivot changes the first matching element.
+	assert.Equal(t, 7, list.LInsert(key, Before, []byte("c"), []byte("c")))
+	assert.Equal(t, 8, list.LInsert(key, Before, []byte("c"), []byte("w")))
+	assert.Equal(t, []byte("w"), list.LIndex(key, 0))
+}
+
+func BenchmarkList_LInsert(b *testing.B) {
+	lis := New()
+	for i := 0; i < 10000; i++ {
+		lis.RPush(key, []byte(strconv.Itoa(i)))
+	}
+	pivot := []byte("9999")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lis.LInsert(key, Before, pivot, []byte("x"))
+	}
+}
+
+func TestList_LInsertMany(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("a"), []byte("b"))
+
+	n := list.LInsertMany(key, Before, []byte("b"), []byte("x"), []byte("y"))
+	assert.Equal(t, 4, n)
+	n = list.LInsertMany(key, After, []byte("b"), []byte("m"), []byte("n"))
+	assert.Equal(t, 6, n)
+
+	expected := [][]byte{[]byte("a"), []byte("x"), []byte("y"), []byte("b"), []byte("m"), []byte("n")}
+	assert.Equal(t, expected, list.LRange(key, 0, -1))
+
+	n = list.LInsertMany(key, After, []byte("not"), []byte("z"))
+	assert.Equal(t, -1, n)
+}
+
+func TestList_LInsertByIndex(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("a"), []byte("a"), []byte("a"))
+
+	assert.Equal(t, 4, list.LInsertByIndex(key, 1, Before, []byte("x")))
+	assert.Equal(t, 5, list.LInsertByIndex(key, -1, After, []byte("y")))
+	expected := [][]byte{[]byte("a"), []byte("x"), []byte("a"), []byte("a"), []byte("y")}
+	assert.Equal(t, expected, list.LRange(key, 0, -1))
+
+	assert.Equal(t, -1, list.LInsertByIndex(key, 5, Before, []byte("z")))
+	assert.Equal(t, -1, list.LInsertByIndex("not", 0, Before, []byte("z")))
+}
+
+func TestList_LInsertSorted(t *testing.T) {
+	list := New()
+
+	assert.Equal(t, 0, list.LInsertSorted(key, []byte("d"), nil))
+	assert.Equal(t, 0, list.LInsertSorted(key, []byte("b"), nil))
+	assert.Equal(t, 2, list.LInsertSorted(key, []byte("f"), nil))
+	assert.Equal(t, 2, list.LInsertSorted(key, []byte("d"), nil))
+	assert.Equal(t, 0, list.LInsertSorted(key, []byte("a"), nil))
+
+	expected := [][]byte{[]byte("a"), []byte("b"), []byte("d"), []byte("d"), []byte("f")}
+	assert.Equal(t, expected, list.LRange(key, 0, -1))
+
+	// equal elements keep their insertion order.
+	byLen := func(a, b []byte) bool {
+		return len(a) < len(b)
+	}
+	list.LInsertSorted("len", []byte("xx"), byLen)
+	list.LInsertSorted("len", []byte("a"), byLen)
+	list.LInsertSorted("len", []byte("yy"), byLen)
+	expected = [][]byte{[]byte("a"), []byte("xx"), []byte("yy")}
+	assert.Equal(t, expected, list.LRange("len", 0, -1))
+}
+
+func TestList_LSet(t *testing.T) {
+	list := InitList()
+	ok := list.LSet(key, 0, []byte("FF"))
+	t.Log(ok)
 
 	ok = list.LSet(key, 5, []byte("AA"))
 	t.Log(ok)
@@ -184,6 +1171,91 @@ func TestList_LSet(t *testing.T) {
 	PrintListData(list)
 }
 
+func TestList_LGetSet(t *testing.T) {
+	list := InitList()
+
+	assert.Equal(t, []byte("f"), list.LGetSet(key, 0, []byte("x")))
+	assert.Equal(t, []byte("x"), list.LIndex(key, 0))
+	assert.Equal(t, []byte("a"), list.LGetSet(key, -1, []byte("y")))
+	assert.Equal(t, []byte("y"), list.LIndex(key, -1))
+
+	before := list.LRange(key, 0, -1)
+	assert.Nil(t, list.LGetSet(key, 6, []byte("z")))
+	assert.Nil(t, list.LGetSet(key, -7, []byte("z")))
+	assert.Nil(t, list.LGetSet("not", 0, []byte("z")))
+	assert.Equal(t, before, list.LRange(key, 0, -1))
+	assert.False(t, list.LKeyExists("not"))
+}
+
+func TestList_LApplyAt(t *testing.T) {
+	list := InitList()
+	upper := func(old []byte) []byte {
+		return bytes.ToUpper(old)
+	}
+
+	assert.True(t, list.LApplyAt(key, 1, upper))
+	assert.Equal(t, []byte("E"), list.LIndex(key, 1))
+	assert.True(t, list.LApplyAt(key, -1, upper))
+	assert.Equal(t, []byte("A"), list.LIndex(key, -1))
+
+	assert.True(t, list.LApplyAt(key, 0, func(old []byte) []byte { return nil }))
+	assert.Equal(t, []byte{}, list.LIndex(key, 0))
+	assert.Equal(t, 6, list.LLen(key))
+
+	assert.False(t, list.LApplyAt(key, 6, upper))
+	assert.False(t, list.LApplyAt("not", 0, upper))
+	assert.False(t, list.LApplyAt(key, 0, nil))
+}
+
+func TestList_LReplaceAt(t *testing.T) {
+	list := InitList()
+
+	assert.True(t, list.LReplaceAt(key, 1, []byte("e"), []byte("x")))
+	assert.Equal(t, []byte("x"), list.LIndex(key, 1))
+	assert.True(t, list.LReplaceAt(key, -1, []byte("a"), []byte("y")))
+	assert.Equal(t, []byte("y"), list.LIndex(key, -1))
+
+	version := list.LVersion(key)
+	assert.False(t, list.LReplaceAt(key, 0, []byte("e"), []byte("z")))
+	assert.Equal(t, []byte("f"), list.LIndex(key, 0))
+	assert.Equal(t, version, list.LVersion(key))
+
+	assert.False(t, list.LReplaceAt(key, 6, []byte("a"), []byte("z")))
+	assert.False(t, list.LReplaceAt("not", 0, []byte("a"), []byte("z")))
+}
+
+func TestList_LSetRange(t *testing.T) {
+	list := InitList()
+
+	//f e d c b a
+	n := list.LSetRange(key, 1, [][]byte{[]byte("x"), []byte("y")})
+	assert.Equal(t, 2, n)
+	n = list.LSetRange(key, -2, [][]byte{[]byte("m"), []byte("n"), []byte("o")})
+	assert.Equal(t, 2, n)
+
+	expected := [][]byte{[]byte("f"), []byte("x"), []byte("y"), []byte("c"), []byte("m"), []byte("n")}
+	assert.Equal(t, expected, list.LRange(key, 0, -1))
+
+	assert.Equal(t, 0, list.LSetRange(key, 6, [][]byte{[]byte("z")}))
+	assert.Equal(t, 0, list.LSetRange("not", 0, [][]byte{[]byte("z")}))
+}
+
+func TestList_LSwap(t *testing.T) {
+	list := InitList()
+
+	//f e d c b a
+	assert.True(t, list.LSwap(key, 0, -1))
+	assert.Equal(t, []byte("a"), list.LIndex(key, 0))
+	assert.Equal(t, []byte("f"), list.LIndex(key, 5))
+
+	assert.True(t, list.LSwap(key, 2, 2))
+	assert.Equal(t, []byte("d"), list.LIndex(key, 2))
+
+	assert.False(t, list.LSwap(key, 0, 6))
+	assert.False(t, list.LSwap(key, -7, 0))
+	assert.False(t, list.LSwap("not", 0, 0))
+}
+
 func TestList_LRange(t *testing.T) {
 	list := InitList()
 
@@ -201,6 +1273,496 @@ func TestList_LRange(t *testing.T) {
 	printRes(res)
 }
 
+func TestList_LRangeReverse(t *testing.T) {
+	list := InitList()
+
+	assert.Equal(t, [][]byte{[]byte("d"), []byte("e"), []byte("f")}, list.LRangeReverse(key, 0, 2))
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("c")}, list.LRangeReverse(key, -3, -1))
+	assert.Equal(t, [][]byte{[]byte("b"), []byte("c"), []byte("d"), []byte("e")}, list.LRangeReverse(key, 1, 4))
+	assert.Equal(t, [][]byte{[]byte("a")}, list.LRangeReverse(key, 5, 10))
+	assert.Empty(t, list.LRangeReverse(key, 3, 1))
+	assert.Empty(t, list.LRangeReverse("not", 0, -1))
+}
+
+func TestList_LSort(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("c"), []byte("a"), []byte("d"), []byte("b"), []byte("a"))
+
+	assert.True(t, list.LSort(key, false))
+	sorted := [][]byte{[]byte("a"), []byte("a"), []byte("b"), []byte("c"), []byte("d")}
+	assert.Equal(t, sorted, list.LRange(key, 0, -1))
+
+	// sort an already sorted list.
+	assert.True(t, list.LSort(key, false))
+	assert.Equal(t, sorted, list.LRange(key, 0, -1))
+
+	assert.True(t, list.LSort(key, true))
+	assert.Equal(t, [][]byte{[]byte("d"), []byte("c"), []byte("b"), []byte("a"), []byte("a")}, list.LRange(key, 0, -1))
+
+	assert.False(t, list.LSort("not", false))
+}
+
+func TestList_LSortFunc(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("10"), []byte("9"), []byte("100"), []byte("1"))
+
+	ok := list.LSortFunc(key, func(a, b []byte) bool {
+		x, _ := strconv.Atoi(string(a))
+		y, _ := strconv.Atoi(string(b))
+		return x < y
+	})
+	assert.True(t, ok)
+	assert.Equal(t, [][]byte{[]byte("1"), []byte("9"), []byte("10"), []byte("100")}, list.LRange(key, 0, -1))
+
+	assert.False(t, list.LSortFunc(key, nil))
+	assert.False(t, list.LSortFunc("not", func(a, b []byte) bool { return true }))
+}
+
+func TestList_LBinarySearch(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("b"), []byte("d"), []byte("d"), []byte("f"))
+
+	i, ok := list.LBinarySearch(key, []byte("d"), nil)
+	assert.True(t, ok)
+	assert.Equal(t, 1, i)
+	i, ok = list.LBinarySearch(key, []byte("e"), nil)
+	assert.False(t, ok)
+	assert.Equal(t, 3, i)
+	i, ok = list.LBinarySearch(key, []byte("z"), nil)
+	assert.False(t, ok)
+	assert.Equal(t, 4, i)
+
+	desc := func(a, b []byte) bool {
+		return bytes.Compare(a, b) > 0
+	}
+	list.RPush("desc", []byte("f"), []byte("d"), []byte("b"))
+	i, ok = list.LBinarySearch("desc", []byte("b"), desc)
+	assert.True(t, ok)
+	assert.Equal(t, 2, i)
+
+	i, ok = list.LBinarySearch("not", []byte("a"), nil)
+	assert.False(t, ok)
+	assert.Equal(t, 0, i)
+}
+
+func TestList_LShuffle(t *testing.T) {
+	l1, l2 := New(), New()
+	for i := 0; i < 20; i++ {
+		l1.RPush(key, []byte(strconv.Itoa(i)))
+		l2.RPush(key, []byte(strconv.Itoa(i)))
+	}
+	origin := l1.LRange(key, 0, -1)
+
+	assert.True(t, l1.LShuffle(key, 42))
+	assert.True(t, l2.LShuffle(key, 42))
+	assert.Equal(t, l1.LRange(key, 0, -1), l2.LRange(key, 0, -1))
+	assert.NotEqual(t, origin, l1.LRange(key, 0, -1))
+	assert.ElementsMatch(t, origin, l1.LRange(key, 0, -1))
+
+	assert.True(t, l1.LShuffle(key, 0))
+	assert.Equal(t, 20, l1.LLen(key))
+	assert.False(t, l1.LShuffle("not", 42))
+}
+
+func TestList_LSampleRandom(t *testing.T) {
+	list := InitList()
+
+	sample := list.LSampleRandom(key, 3, 42)
+	assert.Equal(t, 3, len(sample))
+	assert.Equal(t, sample, list.LSampleRandom(key, 3, 42))
+
+	seen := make(map[string]bool)
+	for _, v := range sample {
+		assert.True(t, list.LContains(key, v))
+		seen[string(v)] = true
+	}
+	assert.Equal(t, 3, len(seen))
+
+	all := list.LSampleRandom(key, 10, 42)
+	assert.Equal(t, 6, len(all))
+	assert.ElementsMatch(t, list.LRange(key, 0, -1), all)
+
+	all[0][0] = 'z'
+	assert.False(t, list.LContains(key, []byte("z")))
+	assert.Equal(t, 6, list.LLen(key))
+
+	assert.Empty(t, list.LSampleRandom(key, 0, 42))
+	assert.Empty(t, list.LSampleRandom("not", 3, 42))
+}
+
+func TestList_LClone(t *testing.T) {
+	list := InitList()
+	dstKey := "dst_list"
+	list.RPush(dstKey, []byte("x"))
+
+	ok := list.LClone(key, dstKey)
+	assert.True(t, ok)
+	assert.Equal(t, list.LRange(key, 0, -1), list.LRange(dstKey, 0, -1))
+
+	list.LIndex(dstKey, 0)[0] = 'z'
+	list.RPush(dstKey, []byte("y"))
+	assert.Equal(t, []byte("f"), list.LIndex(key, 0))
+	assert.Equal(t, 6, list.LLen(key))
+
+	assert.False(t, list.LClone("not", dstKey))
+}
+
+func TestList_LEqual(t *testing.T) {
+	list := InitList()
+	assert.True(t, list.LEqual(key, key))
+	assert.False(t, list.LEqual(key, "not"))
+	assert.False(t, list.LEqual("not", "not"))
+
+	list.LClone(key, "clone")
+	assert.True(t, list.LEqual(key, "clone"))
+
+	list.LSet("clone", 2, []byte("x"))
+	assert.False(t, list.LEqual(key, "clone"))
+
+	list.RPop("clone")
+	assert.False(t, list.LEqual(key, "clone"))
+
+	list.RPush("e1", []byte("a"))
+	list.RPush("e2", []byte("b"))
+	list.LPop("e1")
+	list.LPop("e2")
+	assert.True(t, list.LEqual("e1", "e2"))
+}
+
+func TestList_LHash(t *testing.T) {
+	list := InitList()
+	list.LClone(key, "clone")
+
+	h := list.LHash(key)
+	assert.NotEqual(t, uint64(0), h)
+	assert.Equal(t, h, list.LHash("clone"))
+
+	list.LSwap("clone", 0, 1)
+	assert.NotEqual(t, h, list.LHash("clone"))
+
+	list.RPush("x", []byte("ab"), []byte("c"))
+	list.RPush("y", []byte("a"), []byte("bc"))
+	assert.NotEqual(t, list.LHash("x"), list.LHash("y"))
+
+	assert.Equal(t, uint64(0), list.LHash("not"))
+}
+
+func TestList_LConcatMove(t *testing.T) {
+	list := New()
+	list.RPush("src", []byte("c"), []byte("d"))
+	list.RPush("dst", []byte("a"), []byte("b"))
+
+	assert.Equal(t, 4, list.LConcatMove("dst", "src"))
+	assert.False(t, list.LKeyExists("src"))
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d")}, list.LRange("dst", 0, -1))
+
+	assert.Equal(t, 4, list.LConcatMove("dst", "dst"))
+	assert.Equal(t, 4, list.LConcatMove("dst", "not"))
+
+	assert.Equal(t, 4, list.LConcatMove("new_dst", "dst"))
+	assert.False(t, list.LKeyExists("dst"))
+	assert.Equal(t, 4, list.LLen("new_dst"))
+}
+
+func TestList_LFlattenInto(t *testing.T) {
+	list := New()
+	list.RPush("a", []byte("1"), []byte("2"))
+	list.RPush("b", []byte("3"))
+	list.RPush("dst", []byte("0"))
+
+	n := list.LFlattenInto("dst", "a", "not", "b", "a")
+	assert.Equal(t, 6, n)
+	expected := [][]byte{[]byte("0"), []byte("1"), []byte("2"), []byte("3"), []byte("1"), []byte("2")}
+	assert.Equal(t, expected, list.LRange("dst", 0, -1))
+	assert.Equal(t, 2, list.LLen("a"))
+	assert.Equal(t, 1, list.LLen("b"))
+
+	n = list.LFlattenInto("a", "a")
+	assert.Equal(t, 4, n)
+
+	n = list.LFlattenInto("new", "not")
+	assert.Equal(t, 0, n)
+	assert.False(t, list.LKeyExists("new"))
+}
+
+func TestList_LInterleave(t *testing.T) {
+	list := New()
+	list.RPush("a", []byte("a1"), []byte("a2"), []byte("a3"))
+	list.RPush("b", []byte("b1"))
+
+	assert.Equal(t, 4, list.LInterleave("dst", "a", "b"))
+	expected := [][]byte{[]byte("a1"), []byte("b1"), []byte("a2"), []byte("a3")}
+	assert.Equal(t, expected, list.LRange("dst", 0, -1))
+	assert.Equal(t, 3, list.LLen("a"))
+	assert.Equal(t, 1, list.LLen("b"))
+
+	assert.Equal(t, 1, list.LInterleave("dst", "not", "b"))
+	assert.Equal(t, 2, list.LInterleave("b", "b", "b"))
+	assert.Equal(t, 0, list.LInterleave("dst", "not", "not"))
+	assert.False(t, list.LKeyExists("dst"))
+}
+
+func TestList_LMergeSortedInto(t *testing.T) {
+	list := New()
+	list.RPush("a", []byte("1"), []byte("3"), []byte("5"))
+	list.RPush("b", []byte("2"), []byte("3"), []byte("6"), []byte("7"))
+
+	assert.Equal(t, 7, list.LMergeSortedInto("dst", "a", "b", nil))
+	expected := [][]byte{[]byte("1"), []byte("2"), []byte("3"), []byte("3"), []byte("5"), []byte("6"), []byte("7")}
+	assert.Equal(t, expected, list.LRange("dst", 0, -1))
+	assert.Equal(t, 3, list.LLen("a"))
+
+	// the merge is stable.
+	list = New()
+	list.RPush("a", []byte("a1"), []byte("c1"))
+	list.RPush("b", []byte("a2"), []byte("b2"))
+	byFirstByte := func(a, b []byte) bool { return a[0] < b[0] }
+	assert.Equal(t, 4, list.LMergeSortedInto("dst", "a", "b", byFirstByte))
+	expected = [][]byte{[]byte("a1"), []byte("a2"), []byte("b2"), []byte("c1")}
+	assert.Equal(t, expected, list.LRange("dst", 0, -1))
+
+	assert.Equal(t, 2, list.LMergeSortedInto("dst", "not", "b", nil))
+}
+
+func TestList_LPartition(t *testing.T) {
+	list := InitList()
+	list.RPush("t", []byte("old"))
+	vowel := func(val []byte) bool {
+		return bytes.ContainsAny(val, "aeiou")
+	}
+
+	n1, n2 := list.LPartition(key, "t", "f", vowel)
+	assert.Equal(t, 2, n1)
+	assert.Equal(t, 4, n2)
+	assert.Equal(t, [][]byte{[]byte("e"), []byte("a")}, list.LRange("t", 0, -1))
+	assert.Equal(t, [][]byte{[]byte("f"), []byte("d"), []byte("c"), []byte("b")}, list.LRange("f", 0, -1))
+	assert.Equal(t, 6, list.LLen(key))
+
+	n1, n2 = list.LPartition("not", "t", "f", vowel)
+	assert.Equal(t, 0, n1)
+	assert.Equal(t, 0, n2)
+	assert.False(t, list.LKeyExists("t"))
+	assert.False(t, list.LKeyExists("f"))
+
+	n1, n2 = list.LPartition(key, "t", "f", nil)
+	assert.Equal(t, 0, n1)
+	assert.Equal(t, 0, n2)
+	assert.False(t, list.LKeyExists("t"))
+}
+
+func TestList_LDiffInto(t *testing.T) {
+	list := New()
+	list.RPush("a", []byte("1"), []byte("2"), []byte("3"), []byte("2"), []byte("4"))
+	list.RPush("b", []byte("3"), []byte("5"))
+
+	n := list.LDiffInto("dst", "a", "b")
+	assert.Equal(t, 4, n)
+	assert.Equal(t, [][]byte{[]byte("1"), []byte("2"), []byte("2"), []byte("4")}, list.LRange("dst", 0, -1))
+	assert.Equal(t, 5, list.LLen("a"))
+
+	assert.Equal(t, 5, list.LDiffInto("dst", "a", "not"))
+	assert.Equal(t, 0, list.LDiffInto("dst", "a", "a"))
+	assert.False(t, list.LKeyExists("dst"))
+	assert.Equal(t, 0, list.LDiffInto("dst", "not", "b"))
+}
+
+func TestList_LIntersectInto(t *testing.T) {
+	list := New()
+	list.RPush("a", []byte("1"), []byte("2"), []byte("3"), []byte("2"), []byte("2"))
+	list.RPush("b", []byte("2"), []byte("5"), []byte("2"), []byte("1"))
+	list.RPush("dst", []byte("x"))
+
+	n := list.LIntersectInto("dst", "a", "b")
+	assert.Equal(t, 3, n)
+	assert.Equal(t, [][]byte{[]byte("1"), []byte("2"), []byte("2")}, list.LRange("dst", 0, -1))
+	assert.Equal(t, 5, list.LLen("a"))
+	assert.Equal(t, 4, list.LLen("b"))
+
+	assert.Equal(t, 0, list.LIntersectInto("dst", "a", "not"))
+	assert.False(t, list.LKeyExists("dst"))
+	assert.Equal(t, 0, list.LIntersectInto("dst", "not", "b"))
+}
+
+func TestList_LUnionInto(t *testing.T) {
+	list := New()
+	list.RPush("a", []byte("1"), []byte("2"), []byte("1"), []byte("3"))
+	list.RPush("b", []byte("4"), []byte("2"), []byte("5"), []byte("4"))
+
+	n := list.LUnionInto("dst", "a", "b")
+	assert.Equal(t, 5, n)
+	expected := [][]byte{[]byte("1"), []byte("2"), []byte("3"), []byte("4"), []byte("5")}
+	assert.Equal(t, expected, list.LRange("dst", 0, -1))
+	assert.Equal(t, 4, list.LLen("a"))
+	assert.Equal(t, 4, list.LLen("b"))
+
+	assert.Equal(t, 3, list.LUnionInto("dst", "not", "b"))
+	assert.Equal(t, 0, list.LUnionInto("dst", "not", "not"))
+	assert.False(t, list.LKeyExists("dst"))
+}
+
+func TestList_LToSlice(t *testing.T) {
+	list := InitList()
+
+	vals := list.LToSlice(key)
+	assert.Equal(t, list.LRange(key, 0, -1), vals)
+
+	vals[0][0] = 'z'
+	assert.Equal(t, []byte("f"), list.LIndex(key, 0))
+
+	assert.Empty(t, list.LToSlice("not"))
+}
+
+func TestList_LFromSlice(t *testing.T) {
+	list := InitList()
+	vals := [][]byte{[]byte("x"), []byte("y"), []byte("z")}
+
+	list.LFromSlice(key, vals)
+	assert.Equal(t, vals, list.LRange(key, 0, -1))
+
+	list.LFromSlice("new_list", vals)
+	assert.Equal(t, vals, list.LToSlice("new_list"))
+}
+
+func TestList_LEach(t *testing.T) {
+	list := InitList()
+
+	var indexes []int
+	var vals [][]byte
+	list.LEach(key, func(index int, val []byte) bool {
+		indexes = append(indexes, index)
+		vals = append(vals, val)
+		return string(val) != "d"
+	})
+	assert.Equal(t, []int{0, 1, 2}, indexes)
+	assert.Equal(t, [][]byte{[]byte("f"), []byte("e"), []byte("d")}, vals)
+
+	list.LEach("not", func(index int, val []byte) bool {
+		t.Error("fn should not be called")
+		return true
+	})
+	assert.NotPanics(t, func() { list.LEach(key, nil) })
+}
+
+func TestList_LEachReverse(t *testing.T) {
+	list := InitList()
+
+	var indexes []int
+	var vals [][]byte
+	list.LEachReverse(key, func(index int, val []byte) bool {
+		indexes = append(indexes, index)
+		vals = append(vals, val)
+		return string(val) != "c"
+	})
+	assert.Equal(t, []int{5, 4, 3}, indexes)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("c")}, vals)
+
+	list.LEachReverse("not", func(index int, val []byte) bool {
+		t.Error("fn should not be called")
+		return true
+	})
+	assert.NotPanics(t, func() { list.LEachReverse(key, nil) })
+}
+
+func TestList_LFoldLeft(t *testing.T) {
+	list := InitList()
+	concat := func(acc, val []byte) []byte {
+		return append(acc, val...)
+	}
+
+	assert.Equal(t, []byte(">fedcba"), list.LFoldLeft(key, []byte(">"), concat))
+	assert.Equal(t, []byte(">"), list.LFoldLeft("not", []byte(">"), concat))
+	assert.Equal(t, []byte(">"), list.LFoldLeft(key, []byte(">"), nil))
+	assert.Equal(t, []byte("f"), list.LIndex(key, 0))
+}
+
+func TestList_Iterator(t *testing.T) {
+	list := InitList()
+
+	var vals [][]byte
+	it := list.Iterator(key)
+	for it.Next() {
+		vals = append(vals, it.Value())
+		if string(it.Value()) == "c" {
+			break
+		}
+	}
+	assert.Equal(t, [][]byte{[]byte("f"), []byte("e"), []byte("d"), []byte("c")}, vals)
+
+	it = list.Iterator("not")
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Value())
+}
+
+func TestList_LRangeCopy(t *testing.T) {
+	list := InitList()
+
+	vals := list.LRangeCopy(key, 0, -1)
+	assert.Equal(t, list.LRange(key, 0, -1), vals)
+	for _, v := range vals {
+		v[0] = 'x'
+	}
+	assert.Equal(t, []byte("f"), list.LIndex(key, 0))
+	assert.Equal(t, []byte("a"), list.LIndex(key, -1))
+
+	assert.Empty(t, list.LRangeCopy("not", 0, -1))
+}
+
+func TestList_LRangeStep(t *testing.T) {
+	list := New()
+	for i := 0; i < 10; i++ {
+		list.RPush(key, []byte(strconv.Itoa(i)))
+	}
+
+	assert.Equal(t, list.LRange(key, 0, -1), list.LRangeStep(key, 0, -1, 1))
+	assert.Equal(t, [][]byte{[]byte("0"), []byte("3"), []byte("6"), []byte("9")}, list.LRangeStep(key, 0, -1, 3))
+	// traverse from right to left.
+	assert.Equal(t, [][]byte{[]byte("7"), []byte("9")}, list.LRangeStep(key, -3, -1, 2))
+	assert.Equal(t, [][]byte{[]byte("2"), []byte("6")}, list.LRangeStep(key, 2, 8, 4))
+
+	assert.Nil(t, list.LRangeStep(key, 0, -1, 0))
+	assert.Nil(t, list.LRangeStep(key, 0, -1, -1))
+	assert.Empty(t, list.LRangeStep("not", 0, -1, 1))
+}
+
+func TestList_LRangeFunc(t *testing.T) {
+	list := InitList()
+	upper := func(val []byte) []byte {
+		return bytes.ToUpper(val)
+	}
+
+	assert.Equal(t, [][]byte{[]byte("F"), []byte("E"), []byte("D")}, list.LRangeFunc(key, 0, 2, upper))
+	assert.Equal(t, [][]byte{[]byte("B"), []byte("A")}, list.LRangeFunc(key, -2, -1, upper))
+	assert.Equal(t, list.LRange(key, 1, 4), list.LRangeFunc(key, 1, 4, nil))
+	assert.Nil(t, list.LRangeFunc("not", 0, -1, upper))
+
+	// the stored values are not modified.
+	assert.Equal(t, []byte("f"), list.LIndex(key, 0))
+}
+
+func TestList_LHead(t *testing.T) {
+	list := InitList()
+
+	//f e d c b a
+	assert.Equal(t, [][]byte{[]byte("f"), []byte("e")}, list.LHead(key, 2))
+	assert.Equal(t, 6, len(list.LHead(key, 10)))
+	assert.NotNil(t, list.LHead(key, 0))
+	assert.Empty(t, list.LHead(key, 0))
+	assert.NotNil(t, list.LHead("not", 2))
+	assert.Empty(t, list.LHead("not", 2))
+}
+
+func TestList_LTail(t *testing.T) {
+	list := InitList()
+
+	//f e d c b a
+	assert.Equal(t, [][]byte{[]byte("b"), []byte("a")}, list.LTail(key, 2))
+	assert.Equal(t, 6, len(list.LTail(key, 10)))
+	assert.NotNil(t, list.LTail(key, -1))
+	assert.Empty(t, list.LTail(key, -1))
+	assert.NotNil(t, list.LTail("not", 2))
+	assert.Empty(t, list.LTail("not", 2))
+}
+
 func TestList_LLen(t *testing.T) {
 	list := InitList()
 
@@ -223,6 +1785,23 @@ func TestList_LTrim(t *testing.T) {
 
 		PrintListData(list)
 	})
+
+	t.Run("empty range", func(t *testing.T) {
+		list := InitList()
+
+		trim := list.LTrim(key, 4, 2)
+		assert.True(t, trim)
+		assert.False(t, list.LKeyExists(key))
+		assert.Equal(t, 0, list.LLen(key))
+		assert.Empty(t, list.LRange(key, 0, -1))
+		assert.Nil(t, list.LPop(key))
+
+		err := list.DumpIterate(func(e *storage.Entry) error {
+			t.Errorf("unexpected entry %s", e.Meta.Key)
+			return nil
+		})
+		assert.Nil(t, err)
+	})
 	//
 	//t.Run("large data test", func(t *testing.T) {
 	//	newLIst := New()
@@ -239,6 +1818,377 @@ func TestList_LTrim(t *testing.T) {
 	//})
 }
 
+func BenchmarkList_LTrim(b *testing.B) {
+	vals := make([][]byte, 1000)
+	for i := range vals {
+		vals[i] = []byte(strconv.Itoa(i))
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		lis := New()
+		lis.RPush(key, vals...)
+		b.StartTimer()
+
+		lis.LTrim(key, 100, 200)
+	}
+}
+
+func TestList_LReverse(t *testing.T) {
+	list := InitList()
+
+	//f e d c b a
+	ok := list.LReverse(key)
+	assert.True(t, ok)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d"), []byte("e"), []byte("f")}, list.LRange(key, 0, -1))
+
+	assert.False(t, list.LReverse("not"))
+	list.record["nil_list"] = nil
+	assert.False(t, list.LReverse("nil_list"))
+}
+
+func TestList_LRotate(t *testing.T) {
+	t.Run("left", func(t *testing.T) {
+		list := InitListWith(key, "a", "b", "c", "d")
+		assert.True(t, list.LRotate(key, 1))
+		assert.Equal(t, [][]byte{[]byte("b"), []byte("c"), []byte("d"), []byte("a")}, list.LRange(key, 0, -1))
+	})
+
+	t.Run("right", func(t *testing.T) {
+		list := InitListWith(key, "a", "b", "c", "d")
+		assert.True(t, list.LRotate(key, -1))
+		assert.Equal(t, [][]byte{[]byte("d"), []byte("a"), []byte("b"), []byte("c")}, list.LRange(key, 0, -1))
+	})
+
+	t.Run("wrap", func(t *testing.T) {
+		list := InitListWith(key, "a", "b", "c", "d")
+		assert.True(t, list.LRotate(key, 10))
+		assert.Equal(t, [][]byte{[]byte("c"), []byte("d"), []byte("a"), []byte("b")}, list.LRange(key, 0, -1))
+
+		assert.True(t, list.LRotate(key, -8))
+		assert.Equal(t, [][]byte{[]byte("c"), []byte("d"), []byte("a"), []byte("b")}, list.LRange(key, 0, -1))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		list := New()
+		assert.False(t, list.LRotate(key, 1))
+	})
+}
+
+func TestList_LLenMulti(t *testing.T) {
+	list := InitList()
+	list.RPush("k1", []byte("a"))
+
+	res := list.LLenMulti(key, "k1", "not")
+	assert.Equal(t, map[string]int{key: 6, "k1": 1, "not": 0}, res)
+	assert.Empty(t, list.LLenMulti())
+}
+
+func TestList_LMemUsage(t *testing.T) {
+	lis := New()
+	lis.RPush(key, []byte("a"), []byte("bb"), []byte("ccc"))
+
+	assert.Equal(t, int64(6+3*NodeOverhead), lis.LMemUsage(key))
+	assert.Equal(t, int64(0), lis.LMemUsage("not"))
+}
+
+func TestList_LCompact(t *testing.T) {
+	list := InitList()
+	vals := list.LRange(key, 0, -1)
+	version := list.LVersion(key)
+	list.LContains(key, []byte("c"))
+
+	assert.True(t, list.LCompact(key))
+	assert.Equal(t, vals, list.LRange(key, 0, -1))
+	assert.Equal(t, version, list.LVersion(key))
+	assert.Equal(t, 1, list.LRem(key, []byte("c"), 0))
+	assert.False(t, list.LContains(key, []byte("c")))
+
+	assert.False(t, list.LCompact("not"))
+}
+
+func BenchmarkList_LCompact(b *testing.B) {
+	benchmarks := []struct {
+		name    string
+		compact bool
+	}{
+		{"churn", false},
+		{"churn+compact", true},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			var heap uint64
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				lis := New()
+				for j := 0; j < 100000; j++ {
+					lis.RPush(key, []byte(strconv.Itoa(j)))
+				}
+				lis.LTrim(key, 0, 99)
+				if bm.compact {
+					lis.LCompact(key)
+				}
+
+				var stats runtime.MemStats
+				runtime.GC()
+				runtime.ReadMemStats(&stats)
+				heap += stats.HeapInuse
+				runtime.KeepAlive(lis)
+			}
+			b.ReportMetric(float64(heap)/float64(b.N), "heap-bytes")
+		})
+	}
+}
+
+func TestList_LTrimFront(t *testing.T) {
+	list := InitList()
+
+	//f e d c b a
+	assert.False(t, list.LTrimFront(key, 6))
+	assert.True(t, list.LTrimFront(key, 2))
+	assert.Equal(t, [][]byte{[]byte("f"), []byte("e")}, list.LRange(key, 0, -1))
+
+	assert.True(t, list.LTrimFront(key, 0))
+	assert.False(t, list.LKeyExists(key))
+	assert.False(t, list.LTrimFront(key, 1))
+}
+
+func TestList_LTrimBack(t *testing.T) {
+	list := InitList()
+
+	//f e d c b a
+	assert.False(t, list.LTrimBack(key, 10))
+	assert.True(t, list.LTrimBack(key, 2))
+	assert.Equal(t, [][]byte{[]byte("b"), []byte("a")}, list.LRange(key, 0, -1))
+
+	assert.True(t, list.LTrimBack(key, -1))
+	assert.False(t, list.LKeyExists(key))
+}
+
+func TestList_LPushCapped(t *testing.T) {
+	list := New()
+
+	n := list.LPushCapped(key, 3, []byte("a"), []byte("b"))
+	assert.Equal(t, 2, n)
+	n = list.LPushCapped(key, 3, []byte("c"), []byte("d"))
+	assert.Equal(t, 3, n)
+	assert.Equal(t, [][]byte{[]byte("d"), []byte("c"), []byte("b")}, list.LRange(key, 0, -1))
+
+	n = list.LPushCapped(key, 0, []byte("e"))
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []byte("d"), list.LIndex(key, 0))
+
+	assert.Equal(t, 0, list.LPushCapped("not", -1, []byte("a")))
+	assert.False(t, list.LKeyExists("not"))
+}
+
+func TestList_LClearAll(t *testing.T) {
+	list := InitList()
+	list.RPush("k1", []byte("a"))
+	list.LSetTTL("k1", time.Now().UnixNano())
+	v := list.LVersion("k1")
+
+	list.LClearAll()
+	assert.Equal(t, 0, list.LKeyCount())
+	assert.Equal(t, 0, list.LTotalElements())
+	_, ok := list.LTTL("k1")
+	assert.False(t, ok)
+	assert.Equal(t, uint64(0), list.LVersion("k1"))
+
+	list.RPush("k1", []byte("a"))
+	assert.True(t, list.LVersion("k1") > v)
+}
+
+func TestList_LValidate(t *testing.T) {
+	list := InitList()
+	list.record["nil_list"] = nil
+
+	assert.Equal(t, []string{"nil_list"}, list.LValidate())
+	assert.False(t, list.LKeyExists("nil_list"))
+	assert.True(t, list.LKeyExists(key))
+	assert.Empty(t, list.LValidate())
+}
+
+func TestList_LVersion(t *testing.T) {
+	list := New()
+	assert.Equal(t, uint64(0), list.LVersion(key))
+
+	list.RPush(key, []byte("a"), []byte("b"))
+	v1 := list.LVersion(key)
+	assert.True(t, v1 > 0)
+
+	list.LIndex(key, 0)
+	list.LRange(key, 0, -1)
+	assert.Equal(t, v1, list.LVersion(key))
+
+	list.LSet(key, 0, []byte("c"))
+	v2 := list.LVersion(key)
+	assert.True(t, v2 > v1)
+
+	list.LRem(key, []byte("x"), 0)
+	assert.Equal(t, v2, list.LVersion(key))
+
+	list.LClear(key)
+	assert.Equal(t, uint64(0), list.LVersion(key))
+	list.RPush(key, []byte("a"))
+	assert.True(t, list.LVersion(key) > v2)
+}
+
+func TestList_LSetIfVersion(t *testing.T) {
+	list := InitList()
+
+	v := list.LVersion(key)
+	assert.True(t, list.LSetIfVersion(key, 0, []byte("x"), v))
+	assert.Equal(t, []byte("x"), list.LIndex(key, 0))
+
+	assert.False(t, list.LSetIfVersion(key, 0, []byte("y"), v))
+	assert.Equal(t, []byte("x"), list.LIndex(key, 0))
+
+	v = list.LVersion(key)
+	assert.False(t, list.LSetIfVersion(key, 10, []byte("y"), v))
+	assert.False(t, list.LSetIfVersion("not", 0, []byte("y"), 0))
+}
+
+func TestList_LWatch(t *testing.T) {
+	list := New()
+	ch, cancel := list.LWatch(key)
+	other, cancelOther := list.LWatch("other")
+	defer cancelOther()
+
+	received := func(ch <-chan struct{}) bool {
+		select {
+		case <-ch:
+			return true
+		default:
+			return false
+		}
+	}
+
+	assert.False(t, received(ch))
+	list.RPush(key, []byte("a"))
+	list.RPush(key, []byte("b"))
+	assert.True(t, received(ch))
+	assert.False(t, received(ch))
+	assert.False(t, received(other))
+
+	list.LClear(key)
+	assert.True(t, received(ch))
+
+	list.RPush(key, []byte("a"))
+	list.LClearAll()
+	assert.True(t, received(ch))
+	assert.True(t, received(other))
+
+	cancel()
+	cancel()
+	list.RPush(key, []byte("a"))
+	assert.False(t, received(ch))
+}
+
+func TestList_LWatchWait(t *testing.T) {
+	list := New()
+	ch, cancel := list.LWatch(key)
+	defer cancel()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		list.RPush(key, []byte("a"))
+	}()
+
+	select {
+	case <-ch:
+		assert.Equal(t, 1, list.LLen(key))
+	case <-time.After(time.Second):
+		t.Fatal("no signal received")
+	}
+}
+
+func TestList_LSetTTL(t *testing.T) {
+	list := InitList()
+
+	_, ok := list.LTTL(key)
+	assert.False(t, ok)
+
+	deadline := time.Now().Add(time.Second).UnixNano()
+	assert.True(t, list.LSetTTL(key, deadline))
+	ttl, ok := list.LTTL(key)
+	assert.True(t, ok)
+	assert.Equal(t, deadline, ttl)
+
+	assert.False(t, list.LSetTTL("not", deadline))
+
+	list.LClear(key)
+	_, ok = list.LTTL(key)
+	assert.False(t, ok)
+}
+
+func TestList_LExpireSweep(t *testing.T) {
+	list := InitList()
+	list.RPush("k1", []byte("a"))
+	list.RPush("k2", []byte("a"))
+
+	now := time.Now().UnixNano()
+	list.LSetTTL(key, now-1)
+	list.LSetTTL("k1", now+int64(time.Hour))
+
+	assert.Equal(t, []string{key}, list.LExpireSweep(now))
+	assert.False(t, list.LKeyExists(key))
+	assert.True(t, list.LKeyExists("k1"))
+	assert.True(t, list.LKeyExists("k2"))
+
+	assert.Empty(t, list.LExpireSweep(now))
+	assert.ElementsMatch(t, []string{"k1"}, list.LExpireSweep(now+int64(time.Hour)))
+	assert.True(t, list.LKeyExists("k2"))
+}
+
+func TestList_LExpireOverwrite(t *testing.T) {
+	list := InitList()
+	list.RPush("src", []byte("a"))
+
+	overwrites := map[string]func(dst string){
+		"LClone":      func(dst string) { list.LClone("src", dst) },
+		"LFromSlice":  func(dst string) { list.LFromSlice(dst, [][]byte{[]byte("a")}) },
+		"LInterleave": func(dst string) { list.LInterleave(dst, "src", key) },
+		"LUnionInto":  func(dst string) { list.LUnionInto(dst, "src", key) },
+	}
+	for name, overwrite := range overwrites {
+		list.RPush("dst", []byte("old"))
+		list.LSetTTL("dst", 5)
+		overwrite("dst")
+
+		_, ok := list.LTTL("dst")
+		assert.False(t, ok, name)
+		assert.Empty(t, list.LExpireSweep(10), name)
+		assert.True(t, list.LKeyExists("dst"), name)
+		list.LClear("dst")
+	}
+}
+
+func TestList_LBytesTotal(t *testing.T) {
+	lis := New()
+	lis.RPush(key, []byte("a"), []byte("bb"), []byte(""), []byte("ccc"))
+
+	assert.Equal(t, 6, lis.LBytesTotal(key))
+	assert.Equal(t, int64(6+4*NodeOverhead), lis.LMemUsage(key))
+	assert.Equal(t, 0, lis.LBytesTotal("not"))
+}
+
+func TestList_LTruncateBytes(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("aaa"), []byte("bb"), []byte("cccc"), []byte("d"))
+
+	assert.Equal(t, 0, list.LTruncateBytes(key, 10))
+	assert.Equal(t, 2, list.LTruncateBytes(key, 6))
+	assert.Equal(t, [][]byte{[]byte("aaa"), []byte("bb")}, list.LRange(key, 0, -1))
+	assert.Equal(t, 5, list.LBytesTotal(key))
+
+	assert.Equal(t, 2, list.LTruncateBytes(key, 2))
+	assert.False(t, list.LKeyExists(key))
+	assert.Equal(t, 0, list.LTruncateBytes("not", 0))
+}
+
 func TestList_LKeyExists(t *testing.T) {
 	lis := InitList()
 	ok1 := lis.LKeyExists(key)
@@ -247,3 +2197,203 @@ func TestList_LKeyExists(t *testing.T) {
 	ok2 := lis.LKeyExists("not")
 	t.Log(ok2)
 }
+
+func TestList_LKeys(t *testing.T) {
+	lis := New()
+	keys := lis.LKeys()
+	assert.NotNil(t, keys)
+	assert.Equal(t, 0, len(keys))
+
+	lis.RPush("k1", []byte("a"))
+	lis.RPush("k2", []byte("b"))
+	assert.ElementsMatch(t, []string{"k1", "k2"}, lis.LKeys())
+}
+
+func TestList_LRenameKey(t *testing.T) {
+	list := InitList()
+	list.RPush("dst", []byte("x"))
+	list.LSetTTL(key, 100)
+	version := list.LVersion(key)
+
+	assert.True(t, list.LRenameKey(key, "dst"))
+	assert.False(t, list.LKeyExists(key))
+	assert.Equal(t, 6, list.LLen("dst"))
+	assert.Equal(t, []byte("f"), list.LIndex("dst", 0))
+	assert.Equal(t, version, list.LVersion("dst"))
+	ttl, ok := list.LTTL("dst")
+	assert.True(t, ok)
+	assert.Equal(t, int64(100), ttl)
+
+	assert.True(t, list.LRenameKey("dst", "dst"))
+	assert.Equal(t, 6, list.LLen("dst"))
+	assert.False(t, list.LRenameKey("not", "dst"))
+}
+
+func TestList_LKeyCount(t *testing.T) {
+	lis := New()
+	assert.Equal(t, 0, lis.LKeyCount())
+
+	lis.RPush("k1", []byte("a"))
+	lis.RPush("k2", []byte("a"))
+	assert.Equal(t, 2, lis.LKeyCount())
+}
+
+func TestList_LTotalElements(t *testing.T) {
+	lis := InitList()
+	assert.Equal(t, 6, lis.LTotalElements())
+
+	lis.RPush("k1", []byte("a"), []byte("b"))
+	assert.Equal(t, 8, lis.LTotalElements())
+	assert.Equal(t, 0, New().LTotalElements())
+}
+
+func TestList_LStats(t *testing.T) {
+	lis := New()
+	assert.Equal(t, ListStats{}, lis.LStats())
+
+	lis.RPush("empty", []byte("a"))
+	lis.LPop("empty")
+	lis.RPush("k1", []byte("a"))
+	for i := 0; i < 11; i++ {
+		lis.RPush("k2", []byte(strconv.Itoa(i)))
+	}
+	for i := 0; i < 20000; i++ {
+		lis.RPush("k3", []byte(strconv.Itoa(i)))
+	}
+
+	stats := lis.LStats()
+	assert.Equal(t, 4, stats.Keys)
+	assert.Equal(t, 20012, stats.TotalElements)
+	assert.Equal(t, 20000, stats.MaxLen)
+	assert.Equal(t, "k3", stats.MaxLenKey)
+	assert.Equal(t, [6]int{1, 1, 1, 0, 0, 1}, stats.LenHistogram)
+}
+
+func TestList_LContains(t *testing.T) {
+	lis := InitList()
+	assert.True(t, lis.LContains(key, []byte("a")))
+	assert.True(t, lis.LContains(key, []byte("f")))
+	assert.False(t, lis.LContains(key, []byte("x")))
+	assert.False(t, lis.LContains("not", []byte("a")))
+
+	lis.record["nil_list"] = nil
+	assert.False(t, lis.LContains("nil_list", []byte("a")))
+}
+
+func TestList_LCount(t *testing.T) {
+	lis := New()
+	lis.RPush(key, []byte("a"), []byte("b"), []byte("a"), []byte("a"))
+
+	assert.Equal(t, 3, lis.LCount(key, []byte("a")))
+	assert.Equal(t, 1, lis.LCount(key, []byte("b")))
+	assert.Equal(t, 0, lis.LCount(key, []byte("c")))
+
+	lis.LPopCount(key, 4)
+	assert.Equal(t, 0, lis.LCount(key, []byte("a")))
+	assert.Equal(t, 0, lis.LCount("not", []byte("a")))
+
+	lis.record["nil_list"] = nil
+	assert.Equal(t, 0, lis.LCount("nil_list", []byte("a")))
+}
+
+func TestList_LCountWhere(t *testing.T) {
+	list := New()
+	list.RPush(key, []byte("a"), []byte("bbb"), []byte("cc"), []byte("dddd"))
+	long := func(val []byte) bool {
+		return len(val) > 2
+	}
+
+	assert.Equal(t, 2, list.LCountWhere(key, long))
+	assert.Equal(t, 0, list.LCountWhere(key, nil))
+	assert.Equal(t, 0, list.LCountWhere("not", long))
+}
+
+func TestList_Concurrent(t *testing.T) {
+	lis := New()
+	wg := new(sync.WaitGroup)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				val := []byte(strconv.Itoa(i*1000 + j))
+				lis.LPush(key, val)
+				lis.RPush(key, val)
+				lis.LLen(key)
+				lis.LRange(key, 0, 10)
+				lis.LIndex(key, -1)
+				lis.LPop(key)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 8000, lis.LLen(key))
+}
+
+func TestList_InvalidValue(t *testing.T) {
+	list := InitList()
+	list.record[key].PushFront("not bytes")
+	list.record[key].PushBack(1)
+
+	assert.NotPanics(t, func() {
+		assert.Nil(t, list.LIndex(key, 0))
+		assert.Equal(t, 8, len(list.LRange(key, 0, -1)))
+		assert.Nil(t, list.LPeek(key))
+		assert.Nil(t, list.RPeek(key))
+		assert.Nil(t, list.LPop(key))
+		assert.Nil(t, list.RPop(key))
+		assert.Equal(t, []byte("f"), list.LPop(key))
+	})
+}
+
+func TestList_SetMaxValueSize(t *testing.T) {
+	list := InitList()
+	list.SetMaxValueSize(3)
+	large := []byte("large")
+
+	t.Run("push", func(t *testing.T) {
+		assert.Equal(t, 6, list.LPush(key, []byte("g"), large))
+		assert.Equal(t, 6, list.RPush(key, large))
+		assert.Equal(t, 6, list.LPushX(key, large))
+		assert.Equal(t, 6, list.RPushBatch(key, [][]byte{large}))
+		assert.Equal(t, 0, list.LPush("not", large))
+		assert.False(t, list.LKeyExists("not"))
+		assert.Equal(t, 7, list.LPush(key, []byte("abc")))
+	})
+
+	t.Run("set", func(t *testing.T) {
+		assert.False(t, list.LSet(key, 0, large))
+		assert.Equal(t, []byte("abc"), list.LIndex(key, 0))
+		assert.Equal(t, 0, list.LSetRange(key, 0, [][]byte{[]byte("x"), large}))
+		assert.Equal(t, []byte("abc"), list.LIndex(key, 0))
+		assert.True(t, list.LSet(key, 0, []byte("x")))
+	})
+
+	t.Run("insert", func(t *testing.T) {
+		assert.Equal(t, 7, list.LInsert(key, Before, []byte("a"), large))
+		assert.Equal(t, 7, list.LInsertByIndex(key, 0, After, large))
+		assert.Equal(t, 7, list.LInsertMany(key, After, []byte("a"), []byte("y"), large))
+		assert.Equal(t, 8, list.LInsert(key, Before, []byte("a"), []byte("y")))
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		snapshot := list.Snapshot()
+		snapshot[key] = append(snapshot[key], large)
+		other := New()
+		other.Restore(snapshot)
+		data, err := other.Marshal()
+		assert.Nil(t, err)
+
+		assert.False(t, list.Restore(snapshot))
+		assert.Equal(t, ErrValueTooLarge, list.Unmarshal(data))
+		assert.Equal(t, 8, list.LLen(key))
+		assert.Equal(t, 9, other.LLen(key))
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		list.SetMaxValueSize(0)
+		assert.Equal(t, 9, list.RPush(key, large))
+	})
+}
@@ -0,0 +1,4 @@
+// Package typed provides a generic wrapper of list.List, which encodes and decodes the values with user supplied functions.
+// The values are still stored as []byte in list.List, so dumping and persistence are unchanged.
+// Type parameters require Go 1.21 or later for this module, the package is empty when built with older versions.
+package typed
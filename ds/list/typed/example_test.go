@@ -0,0 +1,41 @@
+//go:build go1.21
+// +build go1.21
+
+package typed
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/roseduan/rosedb/ds/list"
+)
+
+type task struct {
+	ID   int
+	Name string
+}
+
+func Example() {
+	tasks := New(list.New(),
+		func(t task) ([]byte, error) { return json.Marshal(t) },
+		func(data []byte) (t task, err error) {
+			err = json.Unmarshal(data, &t)
+			return
+		},
+	)
+
+	tasks.RPush("tasks", task{1, "build"}, task{2, "test"})
+	tasks.LPush("tasks", task{0, "lint"})
+
+	all, _ := tasks.LRange("tasks", 0, -1)
+	fmt.Println(all)
+
+	t, _ := tasks.LPop("tasks")
+	fmt.Println(t.Name, tasks.LLen("tasks"))
+
+	_, err := tasks.RPop("not_exist")
+	fmt.Println(err)
+	// Output:
+	// [{0 lint} {1 build} {2 test}]
+	// lint 2
+	// ds/list: key not found
+}
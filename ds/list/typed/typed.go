@@ -0,0 +1,101 @@
+//go:build go1.21
+// +build go1.21
+
+package typed
+
+import (
+	"github.com/roseduan/rosedb/ds/list"
+)
+
+type (
+	// MarshalFunc encodes a value to bytes.
+	MarshalFunc[T any] func(v T) ([]byte, error)
+
+	// UnmarshalFunc decodes a value from bytes.
+	UnmarshalFunc[T any] func(data []byte) (T, error)
+
+	// TypedList wraps a list.List and converts the values between T and []byte.
+	TypedList[T any] struct {
+		lis       *list.List
+		marshal   MarshalFunc[T]
+		unmarshal UnmarshalFunc[T]
+	}
+)
+
+// New create a new typed list on top of lis.
+func New[T any](lis *list.List, marshal MarshalFunc[T], unmarshal UnmarshalFunc[T]) *TypedList[T] {
+	return &TypedList[T]{lis: lis, marshal: marshal, unmarshal: unmarshal}
+}
+
+// LPush insert all the specified values at the head of the list stored at key.
+// Nothing is pushed if any of the values fails to be encoded.
+func (tl *TypedList[T]) LPush(key string, vals ...T) (int, error) {
+	encVals, err := tl.encode(vals)
+	if err != nil {
+		return 0, err
+	}
+	return tl.lis.LPush(key, encVals...), nil
+}
+
+// RPush insert all the specified values at the tail of the list stored at key.
+// Nothing is pushed if any of the values fails to be encoded.
+func (tl *TypedList[T]) RPush(key string, vals ...T) (int, error) {
+	encVals, err := tl.encode(vals)
+	if err != nil {
+		return 0, err
+	}
+	return tl.lis.RPush(key, encVals...), nil
+}
+
+// LPop removes and returns the first element of the list stored at key.
+// list.ErrKeyNotFound or list.ErrEmptyList is returned if there is no element.
+func (tl *TypedList[T]) LPop(key string) (T, error) {
+	val, err := tl.lis.LPopE(key)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return tl.unmarshal(val)
+}
+
+// RPop removes and returns the last element of the list stored at key.
+// list.ErrKeyNotFound or list.ErrEmptyList is returned if there is no element.
+func (tl *TypedList[T]) RPop(key string) (T, error) {
+	val, err := tl.lis.RPopE(key)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return tl.unmarshal(val)
+}
+
+// LRange returns the specified elements of the list stored at key, the offsets are the same as list.List.LRange.
+func (tl *TypedList[T]) LRange(key string, start, end int) ([]T, error) {
+	vals := tl.lis.LRange(key, start, end)
+	res := make([]T, 0, len(vals))
+	for _, v := range vals {
+		decVal, err := tl.unmarshal(v)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, decVal)
+	}
+	return res, nil
+}
+
+// LLen returns the length of the list stored at key.
+func (tl *TypedList[T]) LLen(key string) int {
+	return tl.lis.LLen(key)
+}
+
+func (tl *TypedList[T]) encode(vals []T) ([][]byte, error) {
+	encVals := make([][]byte, 0, len(vals))
+	for _, v := range vals {
+		encVal, err := tl.marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		encVals = append(encVals, encVal)
+	}
+	return encVals, nil
+}